@@ -28,7 +28,9 @@
 #ifndef LANGSVR_CONTENT_STREAM_H_
 #define LANGSVR_CONTENT_STREAM_H_
 
+#include <functional>
 #include <string>
+#include <string_view>
 
 #include "langsvr/result.h"
 
@@ -40,11 +42,24 @@ class Writer;
 
 namespace langsvr {
 
+/// ReadContentOptions controls the validation performed by ReadContent()
+struct ReadContentOptions {
+    /// If true, a 'Content-Type' header with a value other than
+    /// 'application/vscode-jsonrpc; charset=utf-8' causes ReadContent() to fail.
+    /// If false, the unrecognized value is only reported to the warning callback.
+    bool strict_content_type = false;
+
+    /// If set, called with a description of each non-fatal issue found in the content header, such
+    /// as a missing or unrecognized 'Content-Type'.
+    std::function<void(std::string_view)> warning;
+};
+
 /// ReadContent reads the next content header prefixed chunk of data from the reader @p reader
 /// @param reader the byte stream reader
+/// @param options the validation options
 /// @see
 /// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#baseProtocol
-Result<std::string> ReadContent(Reader& reader);
+Result<std::string> ReadContent(Reader& reader, const ReadContentOptions& options = {});
 
 /// WriteContent writes the content header prefixed chunk of data to the writer @p writer
 /// @param writer the byte stream writer
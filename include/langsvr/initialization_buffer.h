@@ -0,0 +1,93 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#ifndef LANGSVR_INITIALIZATION_BUFFER_H_
+#define LANGSVR_INITIALIZATION_BUFFER_H_
+
+#include <cstddef>
+#include <deque>
+#include <functional>
+#include <string>
+#include <string_view>
+
+#include "langsvr/result.h"
+
+namespace langsvr {
+
+/// InitializationBuffer sits in front of a session's Receive(), holding back the
+/// 'textDocument/did*' notifications (didOpen, didChange, didSave, didClose, ...) that a client
+/// sends before the 'initialized' notification. Once the 'initialized' notification has been
+/// passed to the session, the held notifications are passed to the session in the order they were
+/// received. All other messages are passed straight through.
+class InitializationBuffer {
+  public:
+    /// Receiver is the function that messages are passed to, usually the session's Receive()
+    using Receiver = std::function<Result<SuccessType>(std::string_view)>;
+
+    /// WarningHandler is the function used to report notifications dropped from the buffer
+    using WarningHandler = std::function<void(std::string_view)>;
+
+    /// The default maximum number of notifications held by the buffer
+    static constexpr size_t kDefaultMaxBuffered = 1000;
+
+    /// Constructor
+    /// @param receiver the function that messages are passed to
+    /// @param max_buffered the maximum number of notifications held by the buffer. Once full, the
+    /// oldest notification is dropped to make room for each new notification. Zero disables
+    /// buffering.
+    explicit InitializationBuffer(Receiver&& receiver, size_t max_buffered = kDefaultMaxBuffered);
+
+    /// SetWarningHandler sets the handler used to report notifications dropped from a full buffer
+    /// @param handler the new warning handler
+    void SetWarningHandler(WarningHandler&& handler) { warning_handler_ = std::move(handler); }
+
+    /// Receive passes the JSON message @p json to the Receiver, or holds it in the buffer if it is
+    /// a 'textDocument/did*' notification received before the 'initialized' notification.
+    /// @param json the incoming JSON message
+    /// @returns the result of the Receiver, or success if the message was buffered. When the
+    /// 'initialized' notification is received, the first failure of the Receiver for it or any of
+    /// the replayed notifications.
+    Result<SuccessType> Receive(std::string_view json);
+
+    /// @returns true once the 'initialized' notification has been received
+    bool Initialized() const { return initialized_; }
+
+    /// @returns the number of notifications currently held in the buffer
+    size_t Buffered() const { return buffered_.size(); }
+
+  private:
+    Receiver receiver_;
+    WarningHandler warning_handler_;
+    size_t max_buffered_ = kDefaultMaxBuffered;
+    std::deque<std::string> buffered_;
+    bool initialized_ = false;
+};
+
+}  // namespace langsvr
+
+#endif  // LANGSVR_INITIALIZATION_BUFFER_H_
@@ -0,0 +1,70 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_ANY_H_
+#define LANGSVR_LSP_ANY_H_
+
+#include "langsvr/json/builder.h"
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// ToLSPAny converts @p value to an LSPAny, by encoding it to JSON
+/// @param value the value to convert. Must be encodable with Encode().
+/// @returns the LSPAny, or a Failure if the value could not be encoded
+template <typename T>
+Result<LSPAny> ToLSPAny(const T& value) {
+    auto b = json::Builder::Create();
+    auto encoded = Encode(value, *b);
+    if (encoded != Success) {
+        return encoded.Failure();
+    }
+    LSPAny out;
+    if (auto res = Decode(*encoded.Get(), out); res != Success) {
+        return res.Failure();
+    }
+    return out;
+}
+
+/// FromLSPAny converts the LSPAny @p any to a T, by decoding it from JSON
+/// @param any the value to convert
+/// @param out the converted value
+/// @returns a Failure if @p any could not be decoded as a T
+template <typename T>
+Result<SuccessType> FromLSPAny(const LSPAny& any, T& out) {
+    auto b = json::Builder::Create();
+    auto encoded = Encode(any, *b);
+    if (encoded != Success) {
+        return encoded.Failure();
+    }
+    return Decode(*encoded.Get(), out);
+}
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_ANY_H_
@@ -0,0 +1,77 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#ifndef LANGSVR_LSP_CODE_ACTIONS_H_
+#define LANGSVR_LSP_CODE_ACTIONS_H_
+
+#include <string_view>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// IsSubKind returns true if the code action kind @p child is equal to, or is a descendant of, the
+/// code action kind @p parent. Code action kinds form a hierarchy of '.' separated identifiers, so
+/// 'refactor.extract.function' is a descendant of 'refactor.extract' and 'refactor', but not of
+/// 'refactor.ex'. Every kind is a descendant of the empty kind.
+/// @param child the code action kind to test
+/// @param parent the parent code action kind
+/// @returns true if @p child is @p parent, or a descendant of @p parent
+bool IsSubKind(std::string_view child, std::string_view parent);
+
+/// IsSubKind returns true if the code action kind @p child is equal to, or is a descendant of, the
+/// code action kind @p parent.
+/// @param child the code action kind to test
+/// @param parent the parent code action kind
+/// @returns true if @p child is @p parent, or a descendant of @p parent
+bool IsSubKind(CodeActionKind child, CodeActionKind parent);
+
+/// FilterCodeActionsByKinds removes the code actions that were not asked for by a
+/// 'textDocument/codeAction' request with the 'context.only' kinds @p only. An action is kept if
+/// its kind is equal to, or a descendant of, one of the kinds in @p only. Actions without a kind
+/// are dropped. If @p only is empty then all the actions are returned.
+/// @param actions the code actions to filter
+/// @param only the code action kinds requested by the client
+/// @returns the filtered code actions, in their original order
+std::vector<CodeAction> FilterCodeActionsByKinds(std::vector<CodeAction> actions,
+                                                 const std::vector<CodeActionKind>& only);
+
+/// FilterCodeActionsByKinds removes the code actions that were not asked for by a
+/// 'textDocument/codeAction' request with the context @p context, using the kinds of
+/// CodeActionContext::only. If the context does not hold any kinds then all the actions are
+/// returned.
+/// @param actions the code actions to filter
+/// @param context the context of the code action request
+/// @returns the filtered code actions, in their original order
+std::vector<CodeAction> FilterCodeActionsByKinds(std::vector<CodeAction> actions,
+                                                 const CodeActionContext& context);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_CODE_ACTIONS_H_
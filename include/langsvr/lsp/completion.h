@@ -0,0 +1,70 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_COMPLETION_H_
+#define LANGSVR_LSP_COMPLETION_H_
+
+#include <cstddef>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// TruncateCompletionList limits the number of items in the completion list @p list to
+/// @p max_items. If the list holds more items than this, the items are stably sorted by their
+/// 'sortText' (or 'label', if the item has no 'sortText'), the items beyond @p max_items are
+/// dropped, and the list is marked as incomplete so that the client re-requests completions as the
+/// user continues typing. A list that already fits is returned unmodified.
+/// @param list the completion list
+/// @param max_items the maximum number of items to keep
+/// @returns the truncated completion list
+CompletionList TruncateCompletionList(CompletionList list, size_t max_items);
+
+/// MapCompletionItemKind maps the completion item kind @p kind to the nearest kind in
+/// @p supported. Kinds without a supported alternative, such as 'Operator', fall back to 'Text'. If
+/// 'Text' is not supported either, @p kind is returned unchanged.
+/// @param kind the completion item kind
+/// @param supported the completion item kinds supported by the client
+/// @returns @p kind if it is supported, otherwise the nearest supported kind
+CompletionItemKind MapCompletionItemKind(CompletionItemKind kind,
+                                         const std::vector<CompletionItemKind>& supported);
+
+/// MapCompletionItemKinds maps the kind of each of the completion items @p items with
+/// MapCompletionItemKind(), using the kinds supported by a client with the completion capabilities
+/// @p capabilities. As in the protocol, a client that does not declare
+/// 'completionItemKind.valueSet' only supports the kinds from 'Text' to 'Reference'.
+/// @param items the completion items
+/// @param capabilities the client's 'textDocument.completion' capabilities, if any
+/// @returns the completion items with their kinds mapped
+std::vector<CompletionItem> MapCompletionItemKinds(
+    std::vector<CompletionItem> items,
+    const Optional<CompletionClientCapabilities>& capabilities);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_COMPLETION_H_
@@ -0,0 +1,112 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_DIAGNOSTICS_H_
+#define LANGSVR_LSP_DIAGNOSTICS_H_
+
+#include <unordered_map>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// StripUnsupportedDiagnosticTags removes the tags that are not in @p supported from each of the
+/// diagnostics. A diagnostic left with no tags has its tags removed entirely.
+/// @param diagnostics the diagnostics to strip
+/// @param supported the diagnostic tags supported by the client
+/// @returns the stripped diagnostics
+std::vector<Diagnostic> StripUnsupportedDiagnosticTags(
+    std::vector<Diagnostic> diagnostics,
+    const std::vector<DiagnosticTag>& supported);
+
+/// StripUnsupportedDiagnosticTags removes the tags that are not supported by a client with the
+/// publish diagnostics capabilities @p capabilities from each of the diagnostics. If the client
+/// does not declare 'tagSupport', all tags are removed.
+/// @param diagnostics the diagnostics to strip
+/// @param capabilities the client's 'textDocument.publishDiagnostics' capabilities, if any
+/// @returns the stripped diagnostics
+std::vector<Diagnostic> StripUnsupportedDiagnosticTags(
+    std::vector<Diagnostic> diagnostics,
+    const Optional<PublishDiagnosticsClientCapabilities>& capabilities);
+
+/// DeduplicateDiagnostics removes the diagnostics that share the same range, message, source and
+/// code as an earlier diagnostic in the list. The first of the duplicates is kept, and the order of
+/// the remaining diagnostics is preserved.
+/// @param diagnostics the diagnostics to deduplicate
+/// @returns the deduplicated diagnostics
+std::vector<Diagnostic> DeduplicateDiagnostics(std::vector<Diagnostic> diagnostics);
+
+/// MergeDiagnosticSets concatenates the diagnostics produced by multiple analysis passes, in the
+/// order of @p sets, and removes the duplicates as DeduplicateDiagnostics() does.
+/// @param sets the diagnostics of each pass
+/// @returns the merged diagnostics
+std::vector<Diagnostic> MergeDiagnosticSets(const std::vector<std::vector<Diagnostic>>& sets);
+
+/// DiagnosticResultCache holds the result id and diagnostics of the last report sent for each
+/// document in response to a 'textDocument/diagnostic' request. This lets a request handler reply
+/// with an 'unchanged' report, when the client already has the latest diagnostics, without keeping
+/// its own cache of result ids.
+class DiagnosticResultCache {
+  public:
+    /// @returns the result id of the last report for the document @p uri, or an unset Optional if
+    /// no report has been made for the document.
+    Optional<String> LastResultId(const DocumentUri& uri) const;
+
+    /// Report returns the report for the 'textDocument/diagnostic' request with the parameters
+    /// @p params.
+    /// If @p diagnostics is set, a full report holding the diagnostics is returned with a new
+    /// result id, which replaces the cached report of the document.
+    /// If @p diagnostics is unset, the diagnostics have not changed since the last report. If the
+    /// request's 'previousResultId' is the id of that report, an 'unchanged' report is returned.
+    /// Otherwise the client does not have the last report, and it is returned again as a full
+    /// report. A document with no cached report has no diagnostics.
+    /// @param params the parameters of the 'textDocument/diagnostic' request
+    /// @param diagnostics the diagnostics of the document, or an unset Optional if they have not
+    /// changed since the last report
+    /// @returns the diagnostic report
+    DocumentDiagnosticReport Report(const DocumentDiagnosticParams& params,
+                                    Optional<std::vector<Diagnostic>> diagnostics);
+
+    /// Forget removes the cached report for the document @p uri, such as when the document is
+    /// closed.
+    /// @param uri the document URI
+    void Forget(const DocumentUri& uri);
+
+  private:
+    /// Entry is the last report for a document
+    struct Entry {
+        String result_id;
+        std::vector<Diagnostic> diagnostics;
+    };
+    std::unordered_map<DocumentUri, Entry> entries_;
+    Uinteger next_result_id_ = 1;
+};
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_DIAGNOSTICS_H_
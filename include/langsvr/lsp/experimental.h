@@ -0,0 +1,131 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_EXPERIMENTAL_H_
+#define LANGSVR_LSP_EXPERIMENTAL_H_
+
+#include <string>
+#include <string_view>
+#include <type_traits>
+#include <utility>
+
+#include "langsvr/lsp/any.h"
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// GetExperimental decodes the section with the name @p key from the 'experimental' capabilities
+/// @p experimental.
+/// @param experimental the ClientCapabilities::experimental or ServerCapabilities::experimental
+/// @param key the name of the experimental section
+/// @returns the decoded section, an empty Optional if there is no section with the name @p key, or
+/// a Failure if the capabilities are not an object, or the section could not be decoded as a T.
+template <typename T>
+Result<Optional<T>> GetExperimental(const Optional<LSPAny>& experimental, std::string_view key) {
+    if (!experimental) {
+        return Optional<T>{};
+    }
+    auto* object = experimental->Get<LSPObject>();
+    if (!object) {
+        return Failure{"'experimental' capabilities is not an object"};
+    }
+    auto it = object->find(std::string(key));
+    if (it == object->end()) {
+        return Optional<T>{};
+    }
+    T out{};
+    if (auto res = FromLSPAny(it->second, out); res != Success) {
+        return Failure{"while decoding experimental capability '" + std::string(key) +
+                       "': " + res.Failure().reason};
+    }
+    return Optional<T>{std::move(out)};
+}
+
+/// GetExperimental decodes the section with the name @p key from the client's 'experimental'
+/// capabilities.
+/// @see GetExperimental(const Optional<LSPAny>&, std::string_view)
+template <typename T>
+Result<Optional<T>> GetExperimental(const ClientCapabilities& capabilities, std::string_view key) {
+    return GetExperimental<T>(capabilities.experimental, key);
+}
+
+/// GetExperimental decodes the section with the name @p key from the server's 'experimental'
+/// capabilities.
+/// @see GetExperimental(const Optional<LSPAny>&, std::string_view)
+template <typename T>
+Result<Optional<T>> GetExperimental(const ServerCapabilities& capabilities, std::string_view key) {
+    return GetExperimental<T>(capabilities.experimental, key);
+}
+
+/// ExperimentalCapabilities composes an 'experimental' capabilities object from the sections of
+/// multiple independent features. Each feature adds its own named section, and a section name can
+/// only be used once, so that one feature cannot silently clobber another.
+class ExperimentalCapabilities {
+  public:
+    /// Add adds the section @p value with the name @p key.
+    /// @param key the name of the experimental section
+    /// @param value the section value. Must be encodable with Encode().
+    /// @returns a Failure if a section with the name @p key has already been added, or the value
+    /// could not be encoded.
+    template <typename T>
+    Result<SuccessType> Add(std::string_view key, const T& value) {
+        if constexpr (std::is_same_v<T, LSPAny>) {
+            return AddAny(key, value);
+        } else {
+            auto any = ToLSPAny(value);
+            if (any != Success) {
+                return any.Failure();
+            }
+            return AddAny(key, any.Move());
+        }
+    }
+
+    /// ApplyTo merges the added sections into the 'experimental' capabilities of @p capabilities.
+    /// Sections already present in the capabilities are preserved.
+    /// @returns a Failure if the capabilities already hold a section with the name of an added
+    /// section, or the existing 'experimental' capabilities are not an object. On failure
+    /// @p capabilities is left unmodified.
+    Result<SuccessType> ApplyTo(ServerCapabilities& capabilities) const;
+
+    /// ApplyTo merges the added sections into the 'experimental' capabilities of @p capabilities.
+    /// @see ApplyTo(ServerCapabilities&)
+    Result<SuccessType> ApplyTo(ClientCapabilities& capabilities) const;
+
+    /// @returns the composed 'experimental' capabilities object
+    const LSPObject& Object() const { return object_; }
+
+  private:
+    Result<SuccessType> AddAny(std::string_view key, LSPAny value);
+    Result<SuccessType> Merge(Optional<LSPAny>& experimental) const;
+
+    LSPObject object_;
+};
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_EXPERIMENTAL_H_
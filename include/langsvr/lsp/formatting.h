@@ -0,0 +1,86 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_FORMATTING_H_
+#define LANGSVR_LSP_FORMATTING_H_
+
+#include <string_view>
+
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// FormattingOverrides holds the formatting options that a server imposes over the client's
+/// preferences. Each field that is set replaces the corresponding FormattingOptions field, and each
+/// field that is unset leaves the client's preference unchanged.
+struct FormattingOverrides {
+    /// Size of a tab in spaces.
+    Optional<Uinteger> tab_size;
+
+    /// Prefer spaces over tabs.
+    Optional<Boolean> insert_spaces;
+
+    /// Trim trailing whitespace on a line.
+    Optional<Boolean> trim_trailing_whitespace;
+
+    /// Insert a newline character at the end of the file if one does not exist.
+    Optional<Boolean> insert_final_newline;
+
+    /// Trim all newlines after the final newline at the end of the file.
+    Optional<Boolean> trim_final_newlines;
+};
+
+/// MergeFormattingOptions applies the server's formatting overrides to the client's formatting
+/// options.
+/// @param client the formatting options sent by the client
+/// @param server the options that the server overrides
+/// @returns the client's options, with the fields set in @p server replaced
+FormattingOptions MergeFormattingOptions(FormattingOptions client,
+                                         const FormattingOverrides& server);
+
+/// FormattingOverridesFromEditorConfig parses the contents of an '.editorconfig' file, returning
+/// the formatting options that it declares for the file at @p path.
+///
+/// The 'indent_style', 'indent_size', 'tab_width', 'trim_trailing_whitespace' and
+/// 'insert_final_newline' properties are supported. The tab size is taken from 'indent_size', or
+/// from 'tab_width' if 'indent_size' is 'tab' or unset. Section globs support '*', '**', '?',
+/// '[...]' and '{a,b}'. Properties with unrecognized values are ignored, as the EditorConfig
+/// specification requires.
+///
+/// Only the single file is considered: the caller is responsible for finding the '.editorconfig'
+/// files that apply to a document, and for applying the outermost first.
+/// @param editorconfig the contents of the '.editorconfig' file
+/// @param path the path of the file being formatted, relative to the directory holding the
+/// '.editorconfig' file, using '/' as the separator
+/// @returns the declared options, or a Failure if the file has a malformed line
+Result<FormattingOverrides> FormattingOverridesFromEditorConfig(std::string_view editorconfig,
+                                                                std::string_view path);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_FORMATTING_H_
@@ -0,0 +1,60 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#ifndef LANGSVR_LSP_LOCATIONS_H_
+#define LANGSVR_LSP_LOCATIONS_H_
+
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// DeduplicateLocations removes the locations that share the same URI and range as an earlier
+/// location in the list. The first of the duplicates is kept, and the order of the remaining
+/// locations is preserved.
+/// @param locations the locations to deduplicate
+/// @returns the deduplicated locations
+std::vector<Location> DeduplicateLocations(std::vector<Location> locations);
+
+/// SortLocations stably sorts the locations by URI, then by the start line and character of their
+/// range, then by the end line and character of their range.
+/// @param locations the locations to sort
+/// @returns the sorted locations
+std::vector<Location> SortLocations(std::vector<Location> locations);
+
+/// NormalizeLocations sorts the locations with SortLocations(), and removes the duplicates as
+/// DeduplicateLocations() does. This is commonly used for the results of a
+/// 'textDocument/references' request that were gathered by multiple analysis passes.
+/// @param locations the locations to normalize
+/// @returns the sorted, deduplicated locations
+std::vector<Location> NormalizeLocations(std::vector<Location> locations);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_LOCATIONS_H_
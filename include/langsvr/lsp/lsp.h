@@ -37,6 +37,7 @@
 #define LANGSVR_LSP_LSP_H_
 
 #include <string>
+#include <string_view>
 #include <tuple>
 #include <unordered_map>
 #include <utility>
@@ -44,6 +45,7 @@
 
 #include "langsvr/lsp/decode.h"
 #include "langsvr/lsp/encode.h"
+#include "langsvr/lsp/message_direction.h"
 #include "langsvr/lsp/message_kind.h"
 #include "langsvr/lsp/one_of.h"
 #include "langsvr/lsp/optional.h"
@@ -93,11 +95,15 @@ enum class TokenFormat;
 struct TextDocumentIdentifier;
 struct Position;
 struct TextDocumentPositionParams;
+struct WorkDoneProgressParams;
+struct PartialResultParams;
 struct ImplementationParams;
 struct Range;
 struct Location;
 struct TextDocumentRegistrationOptions;
+struct WorkDoneProgressOptions;
 struct ImplementationOptions;
+struct StaticRegistrationOptions;
 struct ImplementationRegistrationOptions;
 struct TypeDefinitionParams;
 struct TypeDefinitionOptions;
@@ -115,7 +121,6 @@ struct DocumentColorRegistrationOptions;
 struct ColorPresentationParams;
 struct TextEdit;
 struct ColorPresentation;
-struct WorkDoneProgressOptions;
 struct FoldingRangeParams;
 struct FoldingRange;
 struct FoldingRangeOptions;
@@ -439,10 +444,7 @@ struct SetTraceParams;
 struct LogTraceParams;
 struct CancelParams;
 struct ProgressParams;
-struct WorkDoneProgressParams;
-struct PartialResultParams;
 struct LocationLink;
-struct StaticRegistrationOptions;
 struct InlineValueText;
 struct InlineValueVariableLookup;
 struct InlineValueEvaluatableExpression;
@@ -468,6 +470,12 @@ struct LSPAny;
 
 namespace langsvr::lsp {
 
+/// A hash of the generated protocol surface: method names, message directions and the signatures of
+/// all the declarations. Changes whenever the generated types change, and can be used to invalidate
+/// caches of data produced with a different version of this header.
+static constexpr std::string_view kProtocolSurfaceHash =
+    "cb00b97331c7011c03d9ed825ad9827dc321aebf70ec4a29c6621f5b2a76bce3";
+
 ////////////////////////////////////////////////////////////////////////////////
 // Type aliases
 ////////////////////////////////////////////////////////////////////////////////
@@ -1529,7 +1537,22 @@ struct TextDocumentPositionParams {
 };
 
 /// No documentation available
-struct ImplementationParams : lsp::TextDocumentPositionParams {};
+struct WorkDoneProgressParams {
+    /// An optional token that a server can use to report work done progress.
+    Optional<lsp::ProgressToken> work_done_token;
+};
+
+/// No documentation available
+struct PartialResultParams {
+    /// An optional token that a server can use to report partial results (e.g. streaming) to the
+    /// client.
+    Optional<lsp::ProgressToken> partial_result_token;
+};
+
+/// No documentation available
+struct ImplementationParams : lsp::TextDocumentPositionParams,
+                              lsp::WorkDoneProgressParams,
+                              lsp::PartialResultParams {};
 
 /// A range in a text document expressed as (zero-based) start and end positions. If you want to
 /// specify a range that contains a line including the line ending character(s) then use an end
@@ -1560,21 +1583,38 @@ struct TextDocumentRegistrationOptions {
 };
 
 /// No documentation available
-struct ImplementationOptions {};
+struct WorkDoneProgressOptions {
+    /// No documentation available
+    Optional<Boolean> work_done_progress;
+};
+
+/// No documentation available
+struct ImplementationOptions : lsp::WorkDoneProgressOptions {};
+
+/// Static registration options to be returned in the initialize request.
+struct StaticRegistrationOptions {
+    /// The id used to register the request. The id can be used to deregister the request again. See
+    /// also Registration#id.
+    Optional<String> id;
+};
 
 /// No documentation available
 struct ImplementationRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                           lsp::ImplementationOptions {};
+                                           lsp::ImplementationOptions,
+                                           lsp::StaticRegistrationOptions {};
 
 /// No documentation available
-struct TypeDefinitionParams : lsp::TextDocumentPositionParams {};
+struct TypeDefinitionParams : lsp::TextDocumentPositionParams,
+                              lsp::WorkDoneProgressParams,
+                              lsp::PartialResultParams {};
 
 /// No documentation available
-struct TypeDefinitionOptions {};
+struct TypeDefinitionOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct TypeDefinitionRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                           lsp::TypeDefinitionOptions {};
+                                           lsp::TypeDefinitionOptions,
+                                           lsp::StaticRegistrationOptions {};
 
 /// A workspace folder inside a client.
 struct WorkspaceFolder {
@@ -1617,7 +1657,7 @@ struct ConfigurationParams {
 };
 
 /// Parameters for a DocumentColorRequest.
-struct DocumentColorParams {
+struct DocumentColorParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -1647,14 +1687,15 @@ struct ColorInformation {
 };
 
 /// No documentation available
-struct DocumentColorOptions {};
+struct DocumentColorOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct DocumentColorRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                          lsp::DocumentColorOptions {};
+                                          lsp::DocumentColorOptions,
+                                          lsp::StaticRegistrationOptions {};
 
 /// Parameters for a ColorPresentationRequest.
-struct ColorPresentationParams {
+struct ColorPresentationParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -1691,14 +1732,8 @@ struct ColorPresentation {
     Optional<std::vector<lsp::TextEdit>> additional_text_edits;
 };
 
-/// No documentation available
-struct WorkDoneProgressOptions {
-    /// No documentation available
-    Optional<Boolean> work_done_progress;
-};
-
 /// Parameters for a FoldingRangeRequest.
-struct FoldingRangeParams {
+struct FoldingRangeParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -1737,24 +1772,28 @@ struct FoldingRange {
 };
 
 /// No documentation available
-struct FoldingRangeOptions {};
+struct FoldingRangeOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct FoldingRangeRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                         lsp::FoldingRangeOptions {};
+                                         lsp::FoldingRangeOptions,
+                                         lsp::StaticRegistrationOptions {};
 
 /// No documentation available
-struct DeclarationParams : lsp::TextDocumentPositionParams {};
+struct DeclarationParams : lsp::TextDocumentPositionParams,
+                           lsp::WorkDoneProgressParams,
+                           lsp::PartialResultParams {};
 
 /// No documentation available
-struct DeclarationOptions {};
+struct DeclarationOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct DeclarationRegistrationOptions : lsp::DeclarationOptions,
-                                        lsp::TextDocumentRegistrationOptions {};
+                                        lsp::TextDocumentRegistrationOptions,
+                                        lsp::StaticRegistrationOptions {};
 
 /// A parameter literal used in selection range requests.
-struct SelectionRangeParams {
+struct SelectionRangeParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -1774,11 +1813,12 @@ struct SelectionRange {
 };
 
 /// No documentation available
-struct SelectionRangeOptions {};
+struct SelectionRangeOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct SelectionRangeRegistrationOptions : lsp::SelectionRangeOptions,
-                                           lsp::TextDocumentRegistrationOptions {};
+                                           lsp::TextDocumentRegistrationOptions,
+                                           lsp::StaticRegistrationOptions {};
 
 /// No documentation available
 struct WorkDoneProgressCreateParams {
@@ -1795,7 +1835,7 @@ struct WorkDoneProgressCancelParams {
 /// The parameter of a `textDocument/prepareCallHierarchy` request.
 ///
 /// @since 3.16.0
-struct CallHierarchyPrepareParams : lsp::TextDocumentPositionParams {};
+struct CallHierarchyPrepareParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {};
 
 /// Represents programming constructs like functions or constructors in the context of call
 /// hierarchy.
@@ -1833,18 +1873,19 @@ struct CallHierarchyItem {
 /// Call hierarchy options used during static registration.
 ///
 /// @since 3.16.0
-struct CallHierarchyOptions {};
+struct CallHierarchyOptions : lsp::WorkDoneProgressOptions {};
 
 /// Call hierarchy options used during static or dynamic registration.
 ///
 /// @since 3.16.0
 struct CallHierarchyRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                          lsp::CallHierarchyOptions {};
+                                          lsp::CallHierarchyOptions,
+                                          lsp::StaticRegistrationOptions {};
 
 /// The parameter of a `callHierarchy/incomingCalls` request.
 ///
 /// @since 3.16.0
-struct CallHierarchyIncomingCallsParams {
+struct CallHierarchyIncomingCallsParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// No documentation available
     lsp::CallHierarchyItem item{};
 };
@@ -1864,7 +1905,7 @@ struct CallHierarchyIncomingCall {
 /// The parameter of a `callHierarchy/outgoingCalls` request.
 ///
 /// @since 3.16.0
-struct CallHierarchyOutgoingCallsParams {
+struct CallHierarchyOutgoingCallsParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// No documentation available
     lsp::CallHierarchyItem item{};
 };
@@ -1884,7 +1925,7 @@ struct CallHierarchyOutgoingCall {
 };
 
 /// @since 3.16.0
-struct SemanticTokensParams {
+struct SemanticTokensParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -1926,7 +1967,7 @@ struct SemanticTokensFullDelta {
 };
 
 /// @since 3.16.0
-struct SemanticTokensOptions {
+struct SemanticTokensOptions : lsp::WorkDoneProgressOptions {
     /// No documentation available
     struct Range {};
 
@@ -1942,10 +1983,11 @@ struct SemanticTokensOptions {
 
 /// @since 3.16.0
 struct SemanticTokensRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                           lsp::SemanticTokensOptions {};
+                                           lsp::SemanticTokensOptions,
+                                           lsp::StaticRegistrationOptions {};
 
 /// @since 3.16.0
-struct SemanticTokensDeltaParams {
+struct SemanticTokensDeltaParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -1982,7 +2024,7 @@ struct SemanticTokensDeltaPartialResult {
 };
 
 /// @since 3.16.0
-struct SemanticTokensRangeParams {
+struct SemanticTokensRangeParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -2019,7 +2061,7 @@ struct ShowDocumentResult {
 };
 
 /// No documentation available
-struct LinkedEditingRangeParams : lsp::TextDocumentPositionParams {};
+struct LinkedEditingRangeParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {};
 
 /// The result of a linked editing range request.
 ///
@@ -2035,11 +2077,12 @@ struct LinkedEditingRanges {
 };
 
 /// No documentation available
-struct LinkedEditingRangeOptions {};
+struct LinkedEditingRangeOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct LinkedEditingRangeRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                               lsp::LinkedEditingRangeOptions {};
+                                               lsp::LinkedEditingRangeOptions,
+                                               lsp::StaticRegistrationOptions {};
 
 /// Represents information on a file/folder create.
 ///
@@ -2302,7 +2345,9 @@ struct DeleteFilesParams {
 };
 
 /// No documentation available
-struct MonikerParams : lsp::TextDocumentPositionParams {};
+struct MonikerParams : lsp::TextDocumentPositionParams,
+                       lsp::WorkDoneProgressParams,
+                       lsp::PartialResultParams {};
 
 /// Moniker definition to match LSIF 0.5 moniker definition.
 ///
@@ -2323,7 +2368,7 @@ struct Moniker {
 };
 
 /// No documentation available
-struct MonikerOptions {};
+struct MonikerOptions : lsp::WorkDoneProgressOptions {};
 
 /// No documentation available
 struct MonikerRegistrationOptions : lsp::TextDocumentRegistrationOptions, lsp::MonikerOptions {};
@@ -2331,7 +2376,7 @@ struct MonikerRegistrationOptions : lsp::TextDocumentRegistrationOptions, lsp::M
 /// The parameter of a `textDocument/prepareTypeHierarchy` request.
 ///
 /// @since 3.17.0
-struct TypeHierarchyPrepareParams : lsp::TextDocumentPositionParams {};
+struct TypeHierarchyPrepareParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {};
 
 /// @since 3.17.0
 struct TypeHierarchyItem {
@@ -2367,18 +2412,19 @@ struct TypeHierarchyItem {
 /// Type hierarchy options used during static registration.
 ///
 /// @since 3.17.0
-struct TypeHierarchyOptions {};
+struct TypeHierarchyOptions : lsp::WorkDoneProgressOptions {};
 
 /// Type hierarchy options used during static or dynamic registration.
 ///
 /// @since 3.17.0
 struct TypeHierarchyRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                          lsp::TypeHierarchyOptions {};
+                                          lsp::TypeHierarchyOptions,
+                                          lsp::StaticRegistrationOptions {};
 
 /// The parameter of a `typeHierarchy/supertypes` request.
 ///
 /// @since 3.17.0
-struct TypeHierarchySupertypesParams {
+struct TypeHierarchySupertypesParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// No documentation available
     lsp::TypeHierarchyItem item{};
 };
@@ -2386,7 +2432,7 @@ struct TypeHierarchySupertypesParams {
 /// The parameter of a `typeHierarchy/subtypes` request.
 ///
 /// @since 3.17.0
-struct TypeHierarchySubtypesParams {
+struct TypeHierarchySubtypesParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// No documentation available
     lsp::TypeHierarchyItem item{};
 };
@@ -2404,7 +2450,7 @@ struct InlineValueContext {
 /// A parameter literal used in inline value requests.
 ///
 /// @since 3.17.0
-struct InlineValueParams {
+struct InlineValueParams : lsp::WorkDoneProgressParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -2418,18 +2464,19 @@ struct InlineValueParams {
 /// Inline value options used during static registration.
 ///
 /// @since 3.17.0
-struct InlineValueOptions {};
+struct InlineValueOptions : lsp::WorkDoneProgressOptions {};
 
 /// Inline value options used during static or dynamic registration.
 ///
 /// @since 3.17.0
 struct InlineValueRegistrationOptions : lsp::InlineValueOptions,
-                                        lsp::TextDocumentRegistrationOptions {};
+                                        lsp::TextDocumentRegistrationOptions,
+                                        lsp::StaticRegistrationOptions {};
 
 /// A parameter literal used in inlay hint requests.
 ///
 /// @since 3.17.0
-struct InlayHintParams {
+struct InlayHintParams : lsp::WorkDoneProgressParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -2536,7 +2583,7 @@ struct InlayHint {
 /// Inlay hint options used during static registration.
 ///
 /// @since 3.17.0
-struct InlayHintOptions {
+struct InlayHintOptions : lsp::WorkDoneProgressOptions {
     /// The server provides support to resolve additional information for an inlay hint item.
     Optional<Boolean> resolve_provider;
 };
@@ -2545,12 +2592,13 @@ struct InlayHintOptions {
 ///
 /// @since 3.17.0
 struct InlayHintRegistrationOptions : lsp::InlayHintOptions,
-                                      lsp::TextDocumentRegistrationOptions {};
+                                      lsp::TextDocumentRegistrationOptions,
+                                      lsp::StaticRegistrationOptions {};
 
 /// Parameters of the document diagnostic request.
 ///
 /// @since 3.17.0
-struct DocumentDiagnosticParams {
+struct DocumentDiagnosticParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -2670,7 +2718,7 @@ struct DiagnosticServerCancellationData {
 /// Diagnostic options.
 ///
 /// @since 3.17.0
-struct DiagnosticOptions {
+struct DiagnosticOptions : lsp::WorkDoneProgressOptions {
     /// An optional identifier under which the diagnostics are managed by the client.
     Optional<String> identifier;
 
@@ -2687,7 +2735,8 @@ struct DiagnosticOptions {
 ///
 /// @since 3.17.0
 struct DiagnosticRegistrationOptions : lsp::TextDocumentRegistrationOptions,
-                                       lsp::DiagnosticOptions {};
+                                       lsp::DiagnosticOptions,
+                                       lsp::StaticRegistrationOptions {};
 
 /// A previous result id in a workspace pull request.
 ///
@@ -2703,7 +2752,7 @@ struct PreviousResultId {
 /// Parameters of the workspace diagnostic request.
 ///
 /// @since 3.17.0
-struct WorkspaceDiagnosticParams {
+struct WorkspaceDiagnosticParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The additional identifier provided during registration.
     Optional<String> identifier;
 
@@ -2971,7 +3020,7 @@ struct InlineCompletionContext {
 /// @since 3.18.0
 ///
 /// Proposed in:
-struct InlineCompletionParams : lsp::TextDocumentPositionParams {
+struct InlineCompletionParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {
     /// Additional information about the context in which inline completions were requested.
     lsp::InlineCompletionContext context{};
 };
@@ -3029,7 +3078,7 @@ struct InlineCompletionList {
 /// @since 3.18.0
 ///
 /// Proposed in:
-struct InlineCompletionOptions {};
+struct InlineCompletionOptions : lsp::WorkDoneProgressOptions {};
 
 /// Inline completion options used during static or dynamic registration.
 ///
@@ -3037,7 +3086,8 @@ struct InlineCompletionOptions {};
 ///
 /// Proposed in:
 struct InlineCompletionRegistrationOptions : lsp::InlineCompletionOptions,
-                                             lsp::TextDocumentRegistrationOptions {};
+                                             lsp::TextDocumentRegistrationOptions,
+                                             lsp::StaticRegistrationOptions {};
 
 /// General parameters to register for a notification or to register a provider.
 struct Registration {
@@ -4331,7 +4381,7 @@ struct ClientCapabilities {
 };
 
 /// The initialize parameters
-struct InitializeParamsBase {
+struct InitializeParamsBase : lsp::WorkDoneProgressParams {
     /// The process Id of the parent process that started the server. Is `null` if the process has
     /// not been started by another process. If the parent process is not alive then the server
     /// should exit.
@@ -4465,7 +4515,8 @@ struct NotebookDocumentSyncOptions {
 /// Registration options specific to a notebook.
 ///
 /// @since 3.17.0
-struct NotebookDocumentSyncRegistrationOptions : lsp::NotebookDocumentSyncOptions {};
+struct NotebookDocumentSyncRegistrationOptions : lsp::NotebookDocumentSyncOptions,
+                                                 lsp::StaticRegistrationOptions {};
 
 /// @since 3.18.0
 ///
@@ -4479,7 +4530,7 @@ struct ServerCompletionItemOptions {
 };
 
 /// Completion options.
-struct CompletionOptions {
+struct CompletionOptions : lsp::WorkDoneProgressOptions {
     /// Most tools trigger completion request automatically without explicitly requesting it using a
     /// keyboard shortcut (e.g. Ctrl+Space). Typically they do so when the user starts to type an
     /// identifier. For example if the user types `c` in a JavaScript file code complete will
@@ -4508,10 +4559,10 @@ struct CompletionOptions {
 };
 
 /// Hover options.
-struct HoverOptions {};
+struct HoverOptions : lsp::WorkDoneProgressOptions {};
 
 /// Server Capabilities for a SignatureHelpRequest.
-struct SignatureHelpOptions {
+struct SignatureHelpOptions : lsp::WorkDoneProgressOptions {
     /// List of characters that trigger signature help automatically.
     Optional<std::vector<String>> trigger_characters;
 
@@ -4524,16 +4575,16 @@ struct SignatureHelpOptions {
 };
 
 /// Server Capabilities for a DefinitionRequest.
-struct DefinitionOptions {};
+struct DefinitionOptions : lsp::WorkDoneProgressOptions {};
 
 /// Reference options.
-struct ReferenceOptions {};
+struct ReferenceOptions : lsp::WorkDoneProgressOptions {};
 
 /// Provider options for a DocumentHighlightRequest.
-struct DocumentHighlightOptions {};
+struct DocumentHighlightOptions : lsp::WorkDoneProgressOptions {};
 
 /// Provider options for a DocumentSymbolRequest.
-struct DocumentSymbolOptions {
+struct DocumentSymbolOptions : lsp::WorkDoneProgressOptions {
     /// A human-readable string that is shown when multiple outlines trees are shown for the same
     /// document.
     ///
@@ -4542,7 +4593,7 @@ struct DocumentSymbolOptions {
 };
 
 /// Provider options for a CodeActionRequest.
-struct CodeActionOptions {
+struct CodeActionOptions : lsp::WorkDoneProgressOptions {
     /// CodeActionKinds that this server may return. The list of kinds may be generic, such as
     /// `CodeActionKind.Refactor`, or the server may list out every specific kind they provide.
     Optional<std::vector<lsp::CodeActionKind>> code_action_kinds;
@@ -4554,19 +4605,19 @@ struct CodeActionOptions {
 };
 
 /// Code Lens provider options of a CodeLensRequest.
-struct CodeLensOptions {
+struct CodeLensOptions : lsp::WorkDoneProgressOptions {
     /// Code lens has a resolve provider as well.
     Optional<Boolean> resolve_provider;
 };
 
 /// Provider options for a DocumentLinkRequest.
-struct DocumentLinkOptions {
+struct DocumentLinkOptions : lsp::WorkDoneProgressOptions {
     /// Document links have a resolve provider as well.
     Optional<Boolean> resolve_provider;
 };
 
 /// Server capabilities for a WorkspaceSymbolRequest.
-struct WorkspaceSymbolOptions {
+struct WorkspaceSymbolOptions : lsp::WorkDoneProgressOptions {
     /// The server provides support to resolve additional information for a workspace symbol.
     ///
     /// @since 3.17.0
@@ -4574,10 +4625,10 @@ struct WorkspaceSymbolOptions {
 };
 
 /// Provider options for a DocumentFormattingRequest.
-struct DocumentFormattingOptions {};
+struct DocumentFormattingOptions : lsp::WorkDoneProgressOptions {};
 
 /// Provider options for a DocumentRangeFormattingRequest.
-struct DocumentRangeFormattingOptions {
+struct DocumentRangeFormattingOptions : lsp::WorkDoneProgressOptions {
     /// Whether the server supports formatting multiple ranges at once.
     ///
     /// @since 3.18.0
@@ -4596,7 +4647,7 @@ struct DocumentOnTypeFormattingOptions {
 };
 
 /// Provider options for a RenameRequest.
-struct RenameOptions {
+struct RenameOptions : lsp::WorkDoneProgressOptions {
     /// Renames should be checked and tested before being executed.
     ///
     /// @since version 3.12.0
@@ -4604,7 +4655,7 @@ struct RenameOptions {
 };
 
 /// The server capabilities of a ExecuteCommandRequest.
-struct ExecuteCommandOptions {
+struct ExecuteCommandOptions : lsp::WorkDoneProgressOptions {
     /// The commands to be executed on the server
     std::vector<String> commands{};
 };
@@ -5018,7 +5069,9 @@ struct CompletionContext {
 };
 
 /// Completion parameters
-struct CompletionParams : lsp::TextDocumentPositionParams {
+struct CompletionParams : lsp::TextDocumentPositionParams,
+                          lsp::WorkDoneProgressParams,
+                          lsp::PartialResultParams {
     /// The completion context. This is only available it the client specifies to send this using
     /// the client capability `textDocument.completion.contextSupport === true`
     Optional<lsp::CompletionContext> context;
@@ -5239,7 +5292,7 @@ struct CompletionRegistrationOptions : lsp::TextDocumentRegistrationOptions,
                                        lsp::CompletionOptions {};
 
 /// Parameters for a HoverRequest.
-struct HoverParams : lsp::TextDocumentPositionParams {};
+struct HoverParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {};
 
 /// The result of a hover request.
 struct Hover {
@@ -5332,7 +5385,7 @@ struct SignatureHelpContext {
 };
 
 /// Parameters for a SignatureHelpRequest.
-struct SignatureHelpParams : lsp::TextDocumentPositionParams {
+struct SignatureHelpParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {
     /// The signature help context. This is only available if the client specifies to send this
     /// using the client capability `textDocument.signatureHelp.contextSupport === true`
     ///
@@ -5345,7 +5398,9 @@ struct SignatureHelpRegistrationOptions : lsp::TextDocumentRegistrationOptions,
                                           lsp::SignatureHelpOptions {};
 
 /// Parameters for a DefinitionRequest.
-struct DefinitionParams : lsp::TextDocumentPositionParams {};
+struct DefinitionParams : lsp::TextDocumentPositionParams,
+                          lsp::WorkDoneProgressParams,
+                          lsp::PartialResultParams {};
 
 /// Registration options for a DefinitionRequest.
 struct DefinitionRegistrationOptions : lsp::TextDocumentRegistrationOptions,
@@ -5358,7 +5413,9 @@ struct ReferenceContext {
 };
 
 /// Parameters for a ReferencesRequest.
-struct ReferenceParams : lsp::TextDocumentPositionParams {
+struct ReferenceParams : lsp::TextDocumentPositionParams,
+                         lsp::WorkDoneProgressParams,
+                         lsp::PartialResultParams {
     /// No documentation available
     lsp::ReferenceContext context{};
 };
@@ -5368,7 +5425,9 @@ struct ReferenceRegistrationOptions : lsp::TextDocumentRegistrationOptions,
                                       lsp::ReferenceOptions {};
 
 /// Parameters for a DocumentHighlightRequest.
-struct DocumentHighlightParams : lsp::TextDocumentPositionParams {};
+struct DocumentHighlightParams : lsp::TextDocumentPositionParams,
+                                 lsp::WorkDoneProgressParams,
+                                 lsp::PartialResultParams {};
 
 /// A document highlight is a range inside a text document which deserves special attention. Usually
 /// a document highlight is visualized by changing the background color of its range.
@@ -5385,7 +5444,7 @@ struct DocumentHighlightRegistrationOptions : lsp::TextDocumentRegistrationOptio
                                               lsp::DocumentHighlightOptions {};
 
 /// Parameters for a DocumentSymbolRequest.
-struct DocumentSymbolParams {
+struct DocumentSymbolParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The text document.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -5487,7 +5546,7 @@ struct CodeActionContext {
 };
 
 /// The parameters of a CodeActionRequest.
-struct CodeActionParams {
+struct CodeActionParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The document in which the command was invoked.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -5563,7 +5622,7 @@ struct CodeActionRegistrationOptions : lsp::TextDocumentRegistrationOptions,
                                        lsp::CodeActionOptions {};
 
 /// The parameters of a WorkspaceSymbolRequest.
-struct WorkspaceSymbolParams {
+struct WorkspaceSymbolParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// A query string to filter symbols by. Clients may send an empty string here to request all
     /// symbols.
     String query{};
@@ -5597,7 +5656,7 @@ struct WorkspaceSymbol : lsp::BaseSymbolInformation {
 struct WorkspaceSymbolRegistrationOptions : lsp::WorkspaceSymbolOptions {};
 
 /// The parameters of a CodeLensRequest.
-struct CodeLensParams {
+struct CodeLensParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The document to request code lens for.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -5622,7 +5681,7 @@ struct CodeLens {
 struct CodeLensRegistrationOptions : lsp::TextDocumentRegistrationOptions, lsp::CodeLensOptions {};
 
 /// The parameters of a DocumentLinkRequest.
-struct DocumentLinkParams {
+struct DocumentLinkParams : lsp::WorkDoneProgressParams, lsp::PartialResultParams {
     /// The document to provide document links for.
     lsp::TextDocumentIdentifier text_document{};
 };
@@ -5678,7 +5737,7 @@ struct FormattingOptions {
 };
 
 /// The parameters of a DocumentFormattingRequest.
-struct DocumentFormattingParams {
+struct DocumentFormattingParams : lsp::WorkDoneProgressParams {
     /// The document to format.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -5691,7 +5750,7 @@ struct DocumentFormattingRegistrationOptions : lsp::TextDocumentRegistrationOpti
                                                lsp::DocumentFormattingOptions {};
 
 /// The parameters of a DocumentRangeFormattingRequest.
-struct DocumentRangeFormattingParams {
+struct DocumentRangeFormattingParams : lsp::WorkDoneProgressParams {
     /// The document to format.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -5711,7 +5770,7 @@ struct DocumentRangeFormattingRegistrationOptions : lsp::TextDocumentRegistratio
 /// @since 3.18.0
 ///
 /// Proposed in:
-struct DocumentRangesFormattingParams {
+struct DocumentRangesFormattingParams : lsp::WorkDoneProgressParams {
     /// The document to format.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -5745,7 +5804,7 @@ struct DocumentOnTypeFormattingRegistrationOptions : lsp::TextDocumentRegistrati
                                                      lsp::DocumentOnTypeFormattingOptions {};
 
 /// The parameters of a RenameRequest.
-struct RenameParams {
+struct RenameParams : lsp::WorkDoneProgressParams {
     /// The document to rename.
     lsp::TextDocumentIdentifier text_document{};
 
@@ -5761,10 +5820,10 @@ struct RenameParams {
 struct RenameRegistrationOptions : lsp::TextDocumentRegistrationOptions, lsp::RenameOptions {};
 
 /// No documentation available
-struct PrepareRenameParams : lsp::TextDocumentPositionParams {};
+struct PrepareRenameParams : lsp::TextDocumentPositionParams, lsp::WorkDoneProgressParams {};
 
 /// The parameters of a ExecuteCommandRequest.
-struct ExecuteCommandParams {
+struct ExecuteCommandParams : lsp::WorkDoneProgressParams {
     /// The identifier of the actual command handler.
     String command{};
 
@@ -5888,19 +5947,6 @@ struct ProgressParams {
     lsp::LSPAny value{};
 };
 
-/// No documentation available
-struct WorkDoneProgressParams {
-    /// An optional token that a server can use to report work done progress.
-    Optional<lsp::ProgressToken> work_done_token;
-};
-
-/// No documentation available
-struct PartialResultParams {
-    /// An optional token that a server can use to report partial results (e.g. streaming) to the
-    /// client.
-    Optional<lsp::ProgressToken> partial_result_token;
-};
-
 /// Represents the connection of two locations. Provides additional metadata over normal Location
 /// locations, including an origin range.
 struct LocationLink {
@@ -5921,13 +5967,6 @@ struct LocationLink {
     lsp::Range target_selection_range{};
 };
 
-/// Static registration options to be returned in the initialize request.
-struct StaticRegistrationOptions {
-    /// The id used to register the request. The id can be used to deregister the request again. See
-    /// also Registration#id.
-    Optional<String> id;
-};
-
 /// Provide inline value as text.
 ///
 /// @since 3.17.0
@@ -6217,6 +6256,12 @@ Result<const json::Value*> Encode(const Position& in, json::Builder& b);
 Result<SuccessType> Decode(const json::Value& v, TextDocumentPositionParams& out);
 Result<const json::Value*> Encode(const TextDocumentPositionParams& in, json::Builder& b);
 
+Result<SuccessType> Decode(const json::Value& v, WorkDoneProgressParams& out);
+Result<const json::Value*> Encode(const WorkDoneProgressParams& in, json::Builder& b);
+
+Result<SuccessType> Decode(const json::Value& v, PartialResultParams& out);
+Result<const json::Value*> Encode(const PartialResultParams& in, json::Builder& b);
+
 Result<SuccessType> Decode(const json::Value& v, ImplementationParams& out);
 Result<const json::Value*> Encode(const ImplementationParams& in, json::Builder& b);
 
@@ -6229,9 +6274,15 @@ Result<const json::Value*> Encode(const Location& in, json::Builder& b);
 Result<SuccessType> Decode(const json::Value& v, TextDocumentRegistrationOptions& out);
 Result<const json::Value*> Encode(const TextDocumentRegistrationOptions& in, json::Builder& b);
 
+Result<SuccessType> Decode(const json::Value& v, WorkDoneProgressOptions& out);
+Result<const json::Value*> Encode(const WorkDoneProgressOptions& in, json::Builder& b);
+
 Result<SuccessType> Decode(const json::Value& v, ImplementationOptions& out);
 Result<const json::Value*> Encode(const ImplementationOptions& in, json::Builder& b);
 
+Result<SuccessType> Decode(const json::Value& v, StaticRegistrationOptions& out);
+Result<const json::Value*> Encode(const StaticRegistrationOptions& in, json::Builder& b);
+
 Result<SuccessType> Decode(const json::Value& v, ImplementationRegistrationOptions& out);
 Result<const json::Value*> Encode(const ImplementationRegistrationOptions& in, json::Builder& b);
 
@@ -6283,9 +6334,6 @@ Result<const json::Value*> Encode(const TextEdit& in, json::Builder& b);
 Result<SuccessType> Decode(const json::Value& v, ColorPresentation& out);
 Result<const json::Value*> Encode(const ColorPresentation& in, json::Builder& b);
 
-Result<SuccessType> Decode(const json::Value& v, WorkDoneProgressOptions& out);
-Result<const json::Value*> Encode(const WorkDoneProgressOptions& in, json::Builder& b);
-
 Result<SuccessType> Decode(const json::Value& v, FoldingRangeParams& out);
 Result<const json::Value*> Encode(const FoldingRangeParams& in, json::Builder& b);
 
@@ -7282,18 +7330,9 @@ Result<const json::Value*> Encode(const CancelParams& in, json::Builder& b);
 Result<SuccessType> Decode(const json::Value& v, ProgressParams& out);
 Result<const json::Value*> Encode(const ProgressParams& in, json::Builder& b);
 
-Result<SuccessType> Decode(const json::Value& v, WorkDoneProgressParams& out);
-Result<const json::Value*> Encode(const WorkDoneProgressParams& in, json::Builder& b);
-
-Result<SuccessType> Decode(const json::Value& v, PartialResultParams& out);
-Result<const json::Value*> Encode(const PartialResultParams& in, json::Builder& b);
-
 Result<SuccessType> Decode(const json::Value& v, LocationLink& out);
 Result<const json::Value*> Encode(const LocationLink& in, json::Builder& b);
 
-Result<SuccessType> Decode(const json::Value& v, StaticRegistrationOptions& out);
-Result<const json::Value*> Encode(const StaticRegistrationOptions& in, json::Builder& b);
-
 Result<SuccessType> Decode(const json::Value& v, InlineValueText& out);
 Result<const json::Value*> Encode(const InlineValueText& in, json::Builder& b);
 
@@ -7372,11 +7411,23 @@ struct TextDocumentImplementationRequest : lsp::ImplementationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/implementation";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::Definition, std::vector<lsp::DefinitionLink>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/implementation";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::ImplementationRegistrationOptions;
 };
 
 /// A request to resolve the type definition locations of a symbol at a given text document
@@ -7389,11 +7440,23 @@ struct TextDocumentTypeDefinitionRequest : lsp::TypeDefinitionParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/typeDefinition";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::Definition, std::vector<lsp::DefinitionLink>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/typeDefinition";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TypeDefinitionRegistrationOptions;
 };
 
 /// The `workspace/workspaceFolders` is sent from the server to the client to fetch the open
@@ -7405,9 +7468,15 @@ struct WorkspaceWorkspaceFoldersRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/workspaceFolders";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::WorkspaceFolder>, Null>;
 };
@@ -7425,9 +7494,15 @@ struct WorkspaceConfigurationRequest : lsp::ConfigurationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/configuration";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = std::vector<lsp::LSPAny>;
 };
@@ -7442,11 +7517,23 @@ struct TextDocumentDocumentColorRequest : lsp::DocumentColorParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/documentColor";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = std::vector<lsp::ColorInformation>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/documentColor";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentColorRegistrationOptions;
 };
 
 /// A request to list all presentation for a color. The request's parameter is of type
@@ -7459,9 +7546,15 @@ struct TextDocumentColorPresentationRequest : lsp::ColorPresentationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/colorPresentation";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = std::vector<lsp::ColorPresentation>;
 };
@@ -7476,13 +7569,27 @@ struct TextDocumentFoldingRangeRequest : lsp::FoldingRangeParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/foldingRange";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::FoldingRange>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/foldingRange";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FoldingRangeRegistrationOptions;
 };
 
+#ifdef LANGSVR_LSP_3_18
+
 /// @since 3.18.0
 ///
 /// Proposed in:
@@ -7493,12 +7600,19 @@ struct WorkspaceFoldingRangeRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/foldingRange/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = true;
+
     /// The result type of the request
     using Result = Null;
 };
+#endif  // LANGSVR_LSP_3_18
 
 /// A request to resolve the type definition locations of a symbol at a given text document
 /// position. The request's parameter is of type TextDocumentPositionParams the response is of type
@@ -7510,11 +7624,23 @@ struct TextDocumentDeclarationRequest : lsp::DeclarationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/declaration";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::Declaration, std::vector<lsp::DeclarationLink>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/declaration";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DeclarationRegistrationOptions;
 };
 
 /// A request to provide selection ranges in a document. The request's parameter is of type
@@ -7527,11 +7653,23 @@ struct TextDocumentSelectionRangeRequest : lsp::SelectionRangeParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/selectionRange";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::SelectionRange>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/selectionRange";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::SelectionRangeRegistrationOptions;
 };
 
 /// The `window/workDoneProgress/create` request is sent from the server to the client to initiate
@@ -7543,9 +7681,15 @@ struct WindowWorkDoneProgressCreateRequest : lsp::WorkDoneProgressCreateParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "window/workDoneProgress/create";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -7561,11 +7705,23 @@ struct TextDocumentPrepareCallHierarchyRequest : lsp::CallHierarchyPrepareParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/prepareCallHierarchy";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::CallHierarchyItem>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/prepareCallHierarchy";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::CallHierarchyRegistrationOptions;
 };
 
 /// A request to resolve the incoming calls for a given `CallHierarchyItem`.
@@ -7578,9 +7734,15 @@ struct CallHierarchyIncomingCallsRequest : lsp::CallHierarchyIncomingCallsParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "callHierarchy/incomingCalls";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::CallHierarchyIncomingCall>, Null>;
 };
@@ -7595,9 +7757,15 @@ struct CallHierarchyOutgoingCallsRequest : lsp::CallHierarchyOutgoingCallsParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "callHierarchy/outgoingCalls";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::CallHierarchyOutgoingCall>, Null>;
 };
@@ -7610,11 +7778,23 @@ struct TextDocumentSemanticTokensFullRequest : lsp::SemanticTokensParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/semanticTokens/full";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::SemanticTokens, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/semanticTokens";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::SemanticTokensRegistrationOptions;
 };
 
 /// @since 3.16.0
@@ -7625,11 +7805,23 @@ struct TextDocumentSemanticTokensFullDeltaRequest : lsp::SemanticTokensDeltaPara
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/semanticTokens/full/delta";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::SemanticTokens, lsp::SemanticTokensDelta, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/semanticTokens";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::SemanticTokensRegistrationOptions;
 };
 
 /// @since 3.16.0
@@ -7640,9 +7832,15 @@ struct TextDocumentSemanticTokensRangeRequest : lsp::SemanticTokensRangeParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/semanticTokens/range";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::SemanticTokens, Null>;
 };
@@ -7655,9 +7853,15 @@ struct WorkspaceSemanticTokensRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/semanticTokens/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -7674,9 +7878,15 @@ struct WindowShowDocumentRequest : lsp::ShowDocumentParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "window/showDocument";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::ShowDocumentResult;
 };
@@ -7691,11 +7901,23 @@ struct TextDocumentLinkedEditingRangeRequest : lsp::LinkedEditingRangeParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/linkedEditingRange";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::LinkedEditingRanges, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/linkedEditingRange";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::LinkedEditingRangeRegistrationOptions;
 };
 
 /// The will create files request is sent from the client to the server before files are actually
@@ -7711,11 +7933,23 @@ struct WorkspaceWillCreateFilesRequest : lsp::CreateFilesParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/willCreateFiles";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::WorkspaceEdit, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/willCreateFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// The will rename files request is sent from the client to the server before files are actually
@@ -7729,11 +7963,23 @@ struct WorkspaceWillRenameFilesRequest : lsp::RenameFilesParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/willRenameFiles";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::WorkspaceEdit, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/willRenameFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// The did delete files notification is sent from the client to the server when files were deleted
@@ -7747,11 +7993,23 @@ struct WorkspaceWillDeleteFilesRequest : lsp::DeleteFilesParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/willDeleteFiles";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::WorkspaceEdit, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/willDeleteFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// A request to get the moniker of a symbol at a given text document position. The request
@@ -7764,11 +8022,23 @@ struct TextDocumentMonikerRequest : lsp::MonikerParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/moniker";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::Moniker>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/moniker";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::MonikerRegistrationOptions;
 };
 
 /// A request to result a `TypeHierarchyItem` in a document at a given position. Can be used as an
@@ -7782,11 +8052,23 @@ struct TextDocumentPrepareTypeHierarchyRequest : lsp::TypeHierarchyPrepareParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/prepareTypeHierarchy";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TypeHierarchyItem>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/prepareTypeHierarchy";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TypeHierarchyRegistrationOptions;
 };
 
 /// A request to resolve the supertypes for a given `TypeHierarchyItem`.
@@ -7799,9 +8081,15 @@ struct TypeHierarchySupertypesRequest : lsp::TypeHierarchySupertypesParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "typeHierarchy/supertypes";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TypeHierarchyItem>, Null>;
 };
@@ -7816,9 +8104,15 @@ struct TypeHierarchySubtypesRequest : lsp::TypeHierarchySubtypesParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "typeHierarchy/subtypes";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TypeHierarchyItem>, Null>;
 };
@@ -7835,11 +8129,23 @@ struct TextDocumentInlineValueRequest : lsp::InlineValueParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/inlineValue";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::InlineValue>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/inlineValue";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::InlineValueRegistrationOptions;
 };
 
 /// @since 3.17.0
@@ -7850,9 +8156,15 @@ struct WorkspaceInlineValueRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/inlineValue/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -7869,11 +8181,23 @@ struct TextDocumentInlayHintRequest : lsp::InlayHintParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/inlayHint";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::InlayHint>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/inlayHint";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::InlayHintRegistrationOptions;
 };
 
 /// A request to resolve additional properties for an inlay hint. The request's parameter is of type
@@ -7887,9 +8211,15 @@ struct InlayHintResolveRequest : lsp::InlayHint {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "inlayHint/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::InlayHint;
 };
@@ -7902,9 +8232,15 @@ struct WorkspaceInlayHintRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/inlayHint/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -7919,13 +8255,25 @@ struct TextDocumentDiagnosticRequest : lsp::DocumentDiagnosticParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/diagnostic";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::DocumentDiagnosticReport;
     /// The result error type of the request
     using ErrorData = lsp::DiagnosticServerCancellationData;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/diagnostic";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DiagnosticRegistrationOptions;
 };
 
 /// The workspace diagnostic request definition.
@@ -7938,9 +8286,15 @@ struct WorkspaceDiagnosticRequest : lsp::WorkspaceDiagnosticParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/diagnostic";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::WorkspaceDiagnosticReport;
     /// The result error type of the request
@@ -7957,13 +8311,21 @@ struct WorkspaceDiagnosticRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/diagnostic/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
 
+#ifdef LANGSVR_LSP_3_18
+
 /// A request to provide inline completions in a document. The request's parameter is of type
 /// InlineCompletionParams, the response is of type InlineCompletion InlineCompletion[] or a
 /// Thenable that resolves to such.
@@ -7978,12 +8340,25 @@ struct TextDocumentInlineCompletionRequest : lsp::InlineCompletionParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/inlineCompletion";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = true;
+
     /// The result type of the request
     using Result = OneOf<lsp::InlineCompletionList, std::vector<lsp::InlineCompletionItem>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/inlineCompletion";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::InlineCompletionRegistrationOptions;
 };
+#endif  // LANGSVR_LSP_3_18
 
 /// The `client/registerCapability` request is sent from the server to the client to register a new
 /// capability handler on the client side.
@@ -7994,9 +8369,15 @@ struct ClientRegisterCapabilityRequest : lsp::RegistrationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "client/registerCapability";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -8010,9 +8391,15 @@ struct ClientUnregisterCapabilityRequest : lsp::UnregistrationParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "client/unregisterCapability";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -8027,9 +8414,15 @@ struct InitializeRequest : lsp::InitializeParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "initialize";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::InitializeResult;
     /// The result error type of the request
@@ -8046,9 +8439,15 @@ struct ShutdownRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "shutdown";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -8062,9 +8461,15 @@ struct WindowShowMessageRequestRequest : lsp::ShowMessageRequestParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "window/showMessageRequest";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::MessageActionItem, Null>;
 };
@@ -8081,11 +8486,23 @@ struct TextDocumentWillSaveWaitUntilRequest : lsp::WillSaveTextDocumentParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/willSaveWaitUntil";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TextEdit>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/willSaveWaitUntil";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentRegistrationOptions;
 };
 
 /// Request to request completion at a given text document position. The request's parameter is of
@@ -8102,11 +8519,23 @@ struct TextDocumentCompletionRequest : lsp::CompletionParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/completion";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::CompletionItem>, lsp::CompletionList, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/completion";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::CompletionRegistrationOptions;
 };
 
 /// Request to resolve additional information for a given completion item.The request's parameter is
@@ -8119,9 +8548,15 @@ struct CompletionItemResolveRequest : lsp::CompletionItem {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "completionItem/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::CompletionItem;
 };
@@ -8136,11 +8571,23 @@ struct TextDocumentHoverRequest : lsp::HoverParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/hover";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::Hover, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/hover";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::HoverRegistrationOptions;
 };
 
 /// No documentation available
@@ -8151,11 +8598,23 @@ struct TextDocumentSignatureHelpRequest : lsp::SignatureHelpParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/signatureHelp";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::SignatureHelp, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/signatureHelp";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::SignatureHelpRegistrationOptions;
 };
 
 /// A request to resolve the definition location of a symbol at a given text document position. The
@@ -8168,11 +8627,23 @@ struct TextDocumentDefinitionRequest : lsp::DefinitionParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/definition";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::Definition, std::vector<lsp::DefinitionLink>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/definition";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DefinitionRegistrationOptions;
 };
 
 /// A request to resolve project-wide references for the symbol denoted by the given text document
@@ -8185,11 +8656,23 @@ struct TextDocumentReferencesRequest : lsp::ReferenceParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/references";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::Location>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/references";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::ReferenceRegistrationOptions;
 };
 
 /// Request to resolve a DocumentHighlight for a given text document position. The request's
@@ -8202,11 +8685,23 @@ struct TextDocumentDocumentHighlightRequest : lsp::DocumentHighlightParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/documentHighlight";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::DocumentHighlight>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/documentHighlight";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentHighlightRegistrationOptions;
 };
 
 /// A request to list all symbols found in a given text document. The request's parameter is of type
@@ -8219,12 +8714,24 @@ struct TextDocumentDocumentSymbolRequest : lsp::DocumentSymbolParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/documentSymbol";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result =
         OneOf<std::vector<lsp::SymbolInformation>, std::vector<lsp::DocumentSymbol>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/documentSymbol";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentSymbolRegistrationOptions;
 };
 
 /// A request to provide commands for the given text document and range.
@@ -8235,11 +8742,23 @@ struct TextDocumentCodeActionRequest : lsp::CodeActionParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/codeAction";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<OneOf<lsp::Command, lsp::CodeAction>>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/codeAction";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::CodeActionRegistrationOptions;
 };
 
 /// Request to resolve additional information for a given code action.The request's parameter is of
@@ -8251,9 +8770,15 @@ struct CodeActionResolveRequest : lsp::CodeAction {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "codeAction/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::CodeAction;
 };
@@ -8271,12 +8796,24 @@ struct WorkspaceSymbolRequest : lsp::WorkspaceSymbolParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/symbol";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result =
         OneOf<std::vector<lsp::SymbolInformation>, std::vector<lsp::WorkspaceSymbol>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/symbol";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::WorkspaceSymbolRegistrationOptions;
 };
 
 /// A request to resolve the range inside the workspace symbol's location.
@@ -8289,9 +8826,15 @@ struct WorkspaceSymbolResolveRequest : lsp::WorkspaceSymbol {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspaceSymbol/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::WorkspaceSymbol;
 };
@@ -8304,11 +8847,23 @@ struct TextDocumentCodeLensRequest : lsp::CodeLensParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/codeLens";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::CodeLens>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/codeLens";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::CodeLensRegistrationOptions;
 };
 
 /// A request to resolve a command for a given code lens.
@@ -8319,9 +8874,15 @@ struct CodeLensResolveRequest : lsp::CodeLens {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "codeLens/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::CodeLens;
 };
@@ -8336,9 +8897,15 @@ struct WorkspaceCodeLensRefreshRequest {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/codeLens/refresh";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = false;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = Null;
 };
@@ -8351,11 +8918,23 @@ struct TextDocumentDocumentLinkRequest : lsp::DocumentLinkParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/documentLink";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::DocumentLink>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/documentLink";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentLinkRegistrationOptions;
 };
 
 /// Request to resolve additional information for a given document link. The request's parameter is
@@ -8367,9 +8946,15 @@ struct DocumentLinkResolveRequest : lsp::DocumentLink {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "documentLink/resolve";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::DocumentLink;
 };
@@ -8382,11 +8967,23 @@ struct TextDocumentFormattingRequest : lsp::DocumentFormattingParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/formatting";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TextEdit>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/formatting";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentFormattingRegistrationOptions;
 };
 
 /// A request to format a range in a document.
@@ -8397,13 +8994,27 @@ struct TextDocumentRangeFormattingRequest : lsp::DocumentRangeFormattingParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/rangeFormatting";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TextEdit>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/rangeFormatting";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentRangeFormattingRegistrationOptions;
 };
 
+#ifdef LANGSVR_LSP_3_18
+
 /// A request to format ranges in a document.
 ///
 /// @since 3.18.0
@@ -8416,12 +9027,25 @@ struct TextDocumentRangesFormattingRequest : lsp::DocumentRangesFormattingParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/rangesFormatting";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = true;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TextEdit>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/rangesFormatting";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentRangeFormattingRegistrationOptions;
 };
+#endif  // LANGSVR_LSP_3_18
 
 /// A request to format a document on type.
 struct TextDocumentOnTypeFormattingRequest : lsp::DocumentOnTypeFormattingParams {
@@ -8431,11 +9055,23 @@ struct TextDocumentOnTypeFormattingRequest : lsp::DocumentOnTypeFormattingParams
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/onTypeFormatting";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<std::vector<lsp::TextEdit>, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/onTypeFormatting";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DocumentOnTypeFormattingRegistrationOptions;
 };
 
 /// A request to rename a symbol.
@@ -8446,11 +9082,23 @@ struct TextDocumentRenameRequest : lsp::RenameParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/rename";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::WorkspaceEdit, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/rename";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::RenameRegistrationOptions;
 };
 
 /// A request to test and perform the setup necessary for a rename.
@@ -8463,9 +9111,15 @@ struct TextDocumentPrepareRenameRequest : lsp::PrepareRenameParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "textDocument/prepareRename";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::PrepareRenameResult, Null>;
 };
@@ -8479,11 +9133,23 @@ struct WorkspaceExecuteCommandRequest : lsp::ExecuteCommandParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/executeCommand";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = OneOf<lsp::LSPAny, Null>;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/executeCommand";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::ExecuteCommandRegistrationOptions;
 };
 
 /// A request sent from the server to the client to modified certain resources.
@@ -8494,9 +9160,15 @@ struct WorkspaceApplyEditRequest : lsp::ApplyWorkspaceEditParams {
     /// The LSP name for the request
     static constexpr std::string_view kMethod = "workspace/applyEdit";
 
+    /// The direction in which the request is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the request take parameters?
     static constexpr bool kHasParams = true;
 
+    /// Is the request a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
     /// The result type of the request
     using Result = lsp::ApplyWorkspaceEditResult;
 };
@@ -8514,8 +9186,14 @@ struct WorkspaceDidChangeWorkspaceFoldersNotification : lsp::DidChangeWorkspaceF
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didChangeWorkspaceFolders";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The `window/workDoneProgress/cancel` notification is sent from the client to the server to
@@ -8527,8 +9205,14 @@ struct WindowWorkDoneProgressCancelNotification : lsp::WorkDoneProgressCancelPar
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "window/workDoneProgress/cancel";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The did create files notification is sent from the client to the server when files were created
@@ -8542,8 +9226,20 @@ struct WorkspaceDidCreateFilesNotification : lsp::CreateFilesParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didCreateFiles";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/didCreateFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// The did rename files notification is sent from the client to the server when files were renamed
@@ -8557,8 +9253,20 @@ struct WorkspaceDidRenameFilesNotification : lsp::RenameFilesParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didRenameFiles";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/didRenameFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// The will delete files request is sent from the client to the server before files are actually
@@ -8572,8 +9280,20 @@ struct WorkspaceDidDeleteFilesNotification : lsp::DeleteFilesParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didDeleteFiles";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/didDeleteFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::FileOperationRegistrationOptions;
 };
 
 /// A notification sent when a notebook opens.
@@ -8586,8 +9306,14 @@ struct NotebookDocumentDidOpenNotification : lsp::DidOpenNotebookDocumentParams
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "notebookDocument/didOpen";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// No documentation available
@@ -8598,8 +9324,14 @@ struct NotebookDocumentDidChangeNotification : lsp::DidChangeNotebookDocumentPar
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "notebookDocument/didChange";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// A notification sent when a notebook document is saved.
@@ -8612,8 +9344,14 @@ struct NotebookDocumentDidSaveNotification : lsp::DidSaveNotebookDocumentParams
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "notebookDocument/didSave";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// A notification sent when a notebook closes.
@@ -8626,8 +9364,14 @@ struct NotebookDocumentDidCloseNotification : lsp::DidCloseNotebookDocumentParam
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "notebookDocument/didClose";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The initialized notification is sent from the client to the server after the client is fully
@@ -8639,8 +9383,14 @@ struct InitializedNotification : lsp::InitializedParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "initialized";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The exit event is sent from the client to the server to ask the server to exit its process.
@@ -8651,8 +9401,14 @@ struct ExitNotification {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "exit";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = false;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The configuration change notification is sent from the client to the server when the client's
@@ -8665,8 +9421,20 @@ struct WorkspaceDidChangeConfigurationNotification : lsp::DidChangeConfiguration
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didChangeConfiguration";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/didChangeConfiguration";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DidChangeConfigurationRegistrationOptions;
 };
 
 /// The show message notification is sent from a server to a client to ask the client to display a
@@ -8678,8 +9446,14 @@ struct WindowShowMessageNotification : lsp::ShowMessageParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "window/showMessage";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The log message notification is sent from the server to the client to ask the client to log a
@@ -8691,8 +9465,14 @@ struct WindowLogMessageNotification : lsp::LogMessageParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "window/logMessage";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The telemetry event notification is sent from the server to the client to ask the client to log
@@ -8704,8 +9484,14 @@ struct TelemetryEventNotification : lsp::LSPAny {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "telemetry/event";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// The document open notification is sent from the client to the server to signal newly opened text
@@ -8721,8 +9507,20 @@ struct TextDocumentDidOpenNotification : lsp::DidOpenTextDocumentParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/didOpen";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/didOpen";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentRegistrationOptions;
 };
 
 /// The document change notification is sent from the client to the server to signal changes to a
@@ -8734,8 +9532,20 @@ struct TextDocumentDidChangeNotification : lsp::DidChangeTextDocumentParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/didChange";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/didChange";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentChangeRegistrationOptions;
 };
 
 /// The document close notification is sent from the client to the server when the document got
@@ -8751,8 +9561,20 @@ struct TextDocumentDidCloseNotification : lsp::DidCloseTextDocumentParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/didClose";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/didClose";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentRegistrationOptions;
 };
 
 /// The document save notification is sent from the client to the server when the document got saved
@@ -8764,8 +9586,20 @@ struct TextDocumentDidSaveNotification : lsp::DidSaveTextDocumentParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/didSave";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/didSave";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentSaveRegistrationOptions;
 };
 
 /// A document will save notification is sent from the client to the server before the document is
@@ -8777,8 +9611,20 @@ struct TextDocumentWillSaveNotification : lsp::WillSaveTextDocumentParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/willSave";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "textDocument/willSave";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::TextDocumentRegistrationOptions;
 };
 
 /// The watched files notification is sent from the client to the server when the client detects
@@ -8790,8 +9636,20 @@ struct WorkspaceDidChangeWatchedFilesNotification : lsp::DidChangeWatchedFilesPa
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "workspace/didChangeWatchedFiles";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+
+    /// The method used to dynamically register the message with 'client/registerCapability'
+    static constexpr std::string_view kRegistrationMethod = "workspace/didChangeWatchedFiles";
+
+    /// The options used to dynamically register the message
+    using RegistrationOptions = lsp::DidChangeWatchedFilesRegistrationOptions;
 };
 
 /// Diagnostics notification are sent from the server to the client to signal results of validation
@@ -8803,8 +9661,14 @@ struct TextDocumentPublishDiagnosticsNotification : lsp::PublishDiagnosticsParam
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "textDocument/publishDiagnostics";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// No documentation available
@@ -8815,8 +9679,14 @@ struct SetTraceNotification : lsp::SetTraceParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "$/setTrace";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kClientToServer;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// No documentation available
@@ -8827,8 +9697,14 @@ struct LogTraceNotification : lsp::LogTraceParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "$/logTrace";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// No documentation available
@@ -8839,8 +9715,14 @@ struct CancelRequestNotification : lsp::CancelParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "$/cancelRequest";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kBoth;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 /// No documentation available
@@ -8851,8 +9733,14 @@ struct ProgressNotification : lsp::ProgressParams {
     /// The LSP name for the notification
     static constexpr std::string_view kMethod = "$/progress";
 
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kBoth;
+
     /// Does the Notification take parameters?
     static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
 };
 
 }  // namespace langsvr::lsp
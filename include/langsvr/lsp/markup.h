@@ -0,0 +1,87 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_MARKUP_H_
+#define LANGSVR_LSP_MARKUP_H_
+
+#include <string>
+#include <string_view>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/lsp/text_document_sync.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// SanitizeMarkdown removes raw HTML and unsafe links from the Markdown @p markdown, so that it can
+/// be safely rendered by editors that display Markdown as HTML. HTML tags, comments, processing
+/// instructions and declarations are removed, while the text between tags is kept. Autolinks with a
+/// scheme other than http, https or mailto are escaped so they are rendered as text, and such
+/// inline link and link reference definition destinations are replaced with an empty destination.
+/// Markdown formatting, safe links, backslash escapes, code spans and fenced code blocks are
+/// preserved, and HTML inside code spans and code blocks is left untouched, as it is rendered as
+/// text.
+/// @param markdown the Markdown to sanitize
+/// @returns the sanitized Markdown
+std::string SanitizeMarkdown(std::string_view markdown);
+
+/// SanitizeMarkupContent sanitizes @p content with SanitizeMarkdown() if it is Markdown. Plain text
+/// content is returned unmodified.
+/// @param content the content to sanitize
+/// @returns the sanitized content
+MarkupContent SanitizeMarkupContent(MarkupContent content);
+
+/// MergeHovers merges the hovers returned by multiple hover providers into a single hover.
+/// The contents of each hover are concatenated in order, separated by a horizontal rule. The result
+/// is plain text if all the contents are plain text, otherwise it is Markdown, with plain text
+/// escaped and language-tagged MarkedStrings converted to fenced code blocks. The merged range is
+/// the intersection of the hovers' ranges, and is unset if no hover has a range or if the ranges
+/// do not overlap.
+/// @param hovers the hovers to merge. Unset hovers are skipped.
+/// @returns the merged hover, or an unset Optional if no hover is set
+Optional<Hover> MergeHovers(const std::vector<Optional<Hover>>& hovers);
+
+/// HoverWithRange returns a hover with the contents @p content, and a range set to the word at
+/// @p position in the document text @p document, as found by WordRangeAt(). Without a range,
+/// editors highlight the whole line under the cursor instead of the hovered token. The range is
+/// left unset if @p position is not on a word.
+/// @param content the hover contents
+/// @param document the document text
+/// @param position the position of the hover request
+/// @param encoding the negotiated position encoding used for @p position and the hover range
+/// @param is_word the function used to detect the code points of a word
+/// @returns the hover, or a Failure if @p position is not a valid position in @p document
+Result<Hover> HoverWithRange(MarkupContent content,
+                             std::string_view document,
+                             const Position& position,
+                             PositionEncodingKind encoding = PositionEncodingKind::kUTF16,
+                             const WordPredicate& is_word = IsIdentifierCodePoint);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_MARKUP_H_
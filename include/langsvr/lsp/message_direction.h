@@ -0,0 +1,54 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_MESSAGE_DIRECTION_H_
+#define LANGSVR_LSP_MESSAGE_DIRECTION_H_
+
+namespace langsvr::lsp {
+
+/// MessageDirection indicates in which direction a message is sent in the protocol
+enum class MessageDirection {
+    kClientToServer,
+    kServerToClient,
+    kBoth,
+};
+
+/// @param direction the message direction
+/// @returns true if a message with the given direction can be sent by a client
+constexpr bool IsSentByClient(MessageDirection direction) {
+    return direction != MessageDirection::kServerToClient;
+}
+
+/// @param direction the message direction
+/// @returns true if a message with the given direction can be sent by a server
+constexpr bool IsSentByServer(MessageDirection direction) {
+    return direction != MessageDirection::kClientToServer;
+}
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_MESSAGE_DIRECTION_H_
@@ -0,0 +1,162 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_PROGRESS_H_
+#define LANGSVR_LSP_PROGRESS_H_
+
+#include <utility>
+
+#include "langsvr/lsp/any.h"
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// WorkDoneProgress is the value of a '$/progress' notification that reports work done progress.
+/// The 'kind' member of the value determines which of the three phases it holds.
+using WorkDoneProgress = OneOf<WorkDoneProgressBegin, WorkDoneProgressReport, WorkDoneProgressEnd>;
+
+/// NewProgressToken returns a new, unique progress token, such as the token of a
+/// 'window/workDoneProgress/create' request, or the 'workDoneToken' or 'partialResultToken' of a
+/// request sent to the peer.
+/// @returns a ProgressToken holding a random version 4 UUID string
+ProgressToken NewProgressToken();
+
+/// MakeProgressNotification builds the '$/progress' notification for the token @p token with the
+/// typed value @p value.
+/// @param token the progress token, as provided by the receiver of the progress
+/// @param value the progress value, such as a WorkDoneProgressBegin or WorkDoneProgress
+/// @returns the notification, or a Failure if the value could not be encoded
+template <typename T>
+Result<ProgressNotification> MakeProgressNotification(ProgressToken token, const T& value) {
+    auto any = ToLSPAny(value);
+    if (any != Success) {
+        return any.Failure();
+    }
+    ProgressNotification notification;
+    notification.token = std::move(token);
+    notification.value = any.Move();
+    return notification;
+}
+
+/// DecodeProgressValue decodes the value of the '$/progress' notification parameters @p params as
+/// a T.
+/// @param params the progress notification parameters
+/// @returns the decoded value, or a Failure if the value is not a T. Decoding a WorkDoneProgress
+/// fails if the value does not hold a 'kind' of 'begin', 'report' or 'end'.
+template <typename T>
+Result<T> DecodeProgressValue(const ProgressParams& params) {
+    T out{};
+    if (auto res = FromLSPAny(params.value, out); res != Success) {
+        return Failure{"while decoding '$/progress' value: " + res.Failure().reason};
+    }
+    return out;
+}
+
+/// SendProgress sends a '$/progress' notification for the token @p token with the typed value
+/// @p value, using the session @p session.
+/// @param session the Session, ServerSession or ClientSession used to send the notification
+/// @param token the progress token, as provided by the receiver of the progress
+/// @param value the progress value, such as a WorkDoneProgressBegin or WorkDoneProgress
+/// @returns success or failure
+template <typename SESSION, typename T>
+Result<SuccessType> SendProgress(SESSION& session, ProgressToken token, const T& value) {
+    auto notification = MakeProgressNotification(std::move(token), value);
+    if (notification != Success) {
+        return notification.Failure();
+    }
+    return session.Send(notification.Move());
+}
+
+/// WorkDoneProgressReporter reports work done progress for a 'workDoneToken' provided with a
+/// request, such as the 'initialize' request. If no token was provided, each method does nothing
+/// and returns success, so that handlers can report progress without checking whether the progress
+/// was requested.
+/// @tparam SESSION the Session, ServerSession or ClientSession type used to send the progress
+template <typename SESSION>
+class WorkDoneProgressReporter {
+  public:
+    /// Constructor
+    /// @param session the session used to send the '$/progress' notifications
+    /// @param token the work done token, if one was provided
+    WorkDoneProgressReporter(SESSION& session, Optional<ProgressToken> token)
+        : session_(session), token_(std::move(token)) {}
+
+    /// @returns true if a work done token was provided, and progress will be reported
+    bool Enabled() const { return static_cast<bool>(token_); }
+
+    /// Begin reports the start of the work
+    /// @param title the title of the work, such as 'Indexing'
+    /// @param message an optional, more detailed progress message
+    /// @param percentage an optional percentage of the work done, from 0 to 100
+    /// @returns success or failure
+    Result<SuccessType> Begin(String title,
+                              Optional<String> message = {},
+                              Optional<Uinteger> percentage = {}) {
+        WorkDoneProgressBegin begin;
+        begin.title = std::move(title);
+        begin.message = std::move(message);
+        begin.percentage = std::move(percentage);
+        return Send(begin);
+    }
+
+    /// Report reports the progress of the work
+    /// @param message an optional progress message, such as the file being processed
+    /// @param percentage an optional percentage of the work done, from 0 to 100
+    /// @returns success or failure
+    Result<SuccessType> Report(Optional<String> message, Optional<Uinteger> percentage = {}) {
+        WorkDoneProgressReport report;
+        report.message = std::move(message);
+        report.percentage = std::move(percentage);
+        return Send(report);
+    }
+
+    /// End reports the completion of the work
+    /// @param message an optional message describing the outcome of the work
+    /// @returns success or failure
+    Result<SuccessType> End(Optional<String> message = {}) {
+        WorkDoneProgressEnd end;
+        end.message = std::move(message);
+        return Send(end);
+    }
+
+  private:
+    template <typename T>
+    Result<SuccessType> Send(const T& value) {
+        if (!token_) {
+            return Success;
+        }
+        return SendProgress(session_, *token_, value);
+    }
+
+    SESSION& session_;
+    Optional<ProgressToken> token_;
+};
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_PROGRESS_H_
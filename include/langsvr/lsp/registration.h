@@ -0,0 +1,73 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_REGISTRATION_H_
+#define LANGSVR_LSP_REGISTRATION_H_
+
+#include <concepts>
+#include <string>
+#include <string_view>
+#include <utility>
+
+#include "langsvr/lsp/any.h"
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// Registrable is satisfied by request and notification types that support dynamic registration
+template <typename MESSAGE>
+concept Registrable = requires {
+    typename MESSAGE::RegistrationOptions;
+    { MESSAGE::kRegistrationMethod } -> std::convertible_to<std::string_view>;
+};
+
+/// MakeRegistration builds the Registration used with 'client/registerCapability' to dynamically
+/// register the request or notification MESSAGE.
+/// The method and the type of the options are those declared by MESSAGE, so passing options of the
+/// wrong type fails to compile.
+/// @param id the id used to register the message, which can be used to unregister it again
+/// @param options the registration options
+/// @returns the registration, or a Failure if the options could not be encoded
+template <Registrable MESSAGE>
+Result<Registration> MakeRegistration(String id,
+                                      const typename MESSAGE::RegistrationOptions& options) {
+    auto any = ToLSPAny(options);
+    if (any != Success) {
+        return Failure{"while encoding the registration options for '" +
+                       std::string(MESSAGE::kRegistrationMethod) + "': " + any.Failure().reason};
+    }
+    Registration registration;
+    registration.id = std::move(id);
+    registration.method = String(MESSAGE::kRegistrationMethod);
+    registration.register_options = any.Move();
+    return registration;
+}
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_REGISTRATION_H_
@@ -0,0 +1,82 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_RESPONSE_ERROR_H_
+#define LANGSVR_LSP_RESPONSE_ERROR_H_
+
+#include <vector>
+
+#include "langsvr/json/builder.h"
+#include "langsvr/lsp/encode.h"
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// ResponseError is a structured error that a request handler can return in place of its result.
+/// The error is sent as the 'error' of the response, holding the error code, the message and the
+/// request's typed error data.
+///
+/// A request handler returns a ResponseError by returning a
+/// `Result<REQUEST::Result, ResponseError<REQUEST::ErrorData>>`. Only requests with an ErrorData
+/// type, such as InitializeRequest and TextDocumentDiagnosticRequest, can return a ResponseError.
+/// @tparam DATA the type of the error data. Must be the request's ErrorData type.
+template <typename DATA>
+struct ResponseError {
+    /// The error code
+    OneOf<ErrorCodes, LSPErrorCodes, Integer> code = LSPErrorCodes::kRequestFailed;
+
+    /// A short description of the error
+    String message{};
+
+    /// Additional information about the error
+    Optional<DATA> data;
+};
+
+/// Encode encodes the ResponseError @p in as a JSON-RPC response error object
+template <typename DATA>
+Result<const json::Value*> Encode(const ResponseError<DATA>& in, json::Builder& b) {
+    auto code = Encode(in.code, b);
+    if (code != Success) {
+        return code.Failure();
+    }
+    std::vector members{
+        json::Builder::Member{"code", code.Get()},
+        json::Builder::Member{"message", b.String(in.message)},
+    };
+    if (in.data) {
+        auto data = Encode(*in.data, b);
+        if (data != Success) {
+            return data.Failure();
+        }
+        members.push_back(json::Builder::Member{"data", data.Get()});
+    }
+    return b.Object(members);
+}
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_RESPONSE_ERROR_H_
@@ -0,0 +1,213 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_SEMANTIC_TOKENS_H_
+#define LANGSVR_LSP_SEMANTIC_TOKENS_H_
+
+#include <cstddef>
+#include <functional>
+#include <ostream>
+#include <string>
+#include <string_view>
+#include <unordered_map>
+#include <utility>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/lsp/progress.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// SemanticToken is a single semantic token with an absolute position, and the token type and
+/// modifiers resolved against a SemanticTokensLegend.
+struct SemanticToken {
+    /// The zero-based line of the token
+    Uinteger line = 0;
+    /// The zero-based start character of the token, in the negotiated position encoding
+    Uinteger character = 0;
+    /// The length of the token
+    Uinteger length = 0;
+    /// The token type. One of SemanticTokensLegend::token_types
+    String type;
+    /// The token modifiers. Each one of SemanticTokensLegend::token_modifiers
+    std::vector<String> modifiers;
+
+    /// Equality operator
+    bool operator==(const SemanticToken&) const = default;
+};
+
+/// Writes the SemanticToken to the std::ostream
+/// @param out the stream to write to
+/// @param token the semantic token
+/// @return the stream so calls can be chained
+std::ostream& operator<<(std::ostream& out, const SemanticToken& token);
+
+/// EncodeSemanticTokens encodes the list of tokens into the relative integer encoding used by
+/// SemanticTokens::data.
+/// @param legend the legend used to map token types and modifiers to indices
+/// @param tokens the tokens to encode. Must be sorted by position.
+/// @returns the encoded token data, or a Failure if a token type or modifier is not in the legend,
+/// or the tokens are not sorted.
+Result<std::vector<Uinteger>> EncodeSemanticTokens(const SemanticTokensLegend& legend,
+                                                   const std::vector<SemanticToken>& tokens);
+
+/// SemanticTokensStream incrementally encodes semantic tokens into the relative integer encoding
+/// used by SemanticTokens::data, passing the encoded data to a callback in chunks. This allows the
+/// tokens of a large document to be produced and sent as partial results, without holding the
+/// encoding of every token in memory. Each chunk continues the relative encoding of the previous
+/// chunk, so the concatenation of the chunks is the encoding of all the tokens.
+class SemanticTokensStream {
+  public:
+    /// ChunkHandler is the function called with each chunk of encoded token data
+    using ChunkHandler = std::function<Result<SuccessType>(SemanticTokensPartialResult&&)>;
+
+    /// Constructor
+    /// @param legend the legend used to map token types and modifiers to indices
+    /// @param chunk_size the maximum number of integers in each chunk. Chunks only hold whole
+    /// tokens, and hold at least one token.
+    /// @param on_chunk the function called with each chunk
+    SemanticTokensStream(const SemanticTokensLegend& legend,
+                         size_t chunk_size,
+                         ChunkHandler&& on_chunk);
+
+    /// Add encodes the token @p token, passing the pending data to the ChunkHandler once a chunk
+    /// is full.
+    /// @param token the token to add. Tokens must be added in position order.
+    /// @returns a Failure if a token type or modifier is not in the legend, if the token is not in
+    /// position order, or if the ChunkHandler failed.
+    Result<SuccessType> Add(const SemanticToken& token);
+
+    /// Flush passes the pending encoded data, if any, to the ChunkHandler. Flush must be called
+    /// once all the tokens have been added.
+    /// @returns success, or the failure returned by the ChunkHandler
+    Result<SuccessType> Flush();
+
+  private:
+    std::unordered_map<std::string, Uinteger> types_;
+    std::unordered_map<std::string, Uinteger> modifiers_;
+    size_t chunk_size_ = 0;
+    ChunkHandler on_chunk_;
+    std::vector<Uinteger> pending_;
+    Uinteger line_ = 0;
+    Uinteger character_ = 0;
+};
+
+/// StreamSemanticTokens returns a SemanticTokensStream that sends each chunk of encoded tokens as
+/// a SemanticTokensPartialResult, with a '$/progress' notification for the partial result token
+/// @p token. Once all the chunks have been sent, the final result of the request should hold no
+/// tokens.
+/// @param session the Session or ServerSession used to send the partial results
+/// @param token the 'partialResultToken' of the semantic tokens request
+/// @param legend the legend used to map token types and modifiers to indices
+/// @param chunk_size the maximum number of integers in each partial result
+/// @returns the stream
+template <typename SESSION>
+SemanticTokensStream StreamSemanticTokens(SESSION& session,
+                                          ProgressToken token,
+                                          const SemanticTokensLegend& legend,
+                                          size_t chunk_size) {
+    return SemanticTokensStream(
+        legend, chunk_size,
+        [&session, token = std::move(token)](SemanticTokensPartialResult&& chunk) {
+            return SendProgress(session, token, chunk);
+        });
+}
+
+/// DecodeSemanticTokens decodes the relative integer encoding used by SemanticTokens::data into a
+/// list of tokens.
+/// @param legend the legend used to map token type and modifier indices to strings
+/// @param data the encoded token data
+/// @returns the decoded tokens, or a Failure if the data is malformed or references a token type or
+/// modifier that is not in the legend.
+Result<std::vector<SemanticToken>> DecodeSemanticTokens(const SemanticTokensLegend& legend,
+                                                        const std::vector<Uinteger>& data);
+
+/// ValidateSemanticTokens checks that the relative integer encoding used by SemanticTokens::data
+/// is well formed, and that all the token type indices and modifier bits are in range of the
+/// legend.
+/// @param legend the legend used to map token type and modifier indices to strings
+/// @param data the encoded token data
+/// @returns a Failure describing the first problem found, otherwise Success
+Result<SuccessType> ValidateSemanticTokens(const SemanticTokensLegend& legend,
+                                           const std::vector<Uinteger>& data);
+
+/// SemanticTokensLegendBuilder builds a SemanticTokensLegend, assigning each token type and
+/// modifier a stable index. Adding a type or modifier that has already been added returns the
+/// existing index.
+class SemanticTokensLegendBuilder {
+  public:
+    /// AddType adds the token type @p type to the legend
+    /// @returns the index of the token type, as used by SemanticTokens::data
+    Uinteger AddType(std::string_view type);
+
+    /// AddType adds the standard token type @p type to the legend
+    /// @returns the index of the token type, as used by SemanticTokens::data
+    Uinteger AddType(SemanticTokenTypes type);
+
+    /// AddModifier adds the token modifier @p modifier to the legend
+    /// @returns the index of the token modifier, which is the bit used by SemanticTokens::data, or
+    /// a Failure if the legend already holds the maximum of 32 modifiers.
+    Result<Uinteger> AddModifier(std::string_view modifier);
+
+    /// AddModifier adds the standard token modifier @p modifier to the legend
+    /// @returns the index of the token modifier, which is the bit used by SemanticTokens::data, or
+    /// a Failure if the legend already holds the maximum of 32 modifiers.
+    Result<Uinteger> AddModifier(SemanticTokenModifiers modifier);
+
+    /// @returns the built legend
+    const SemanticTokensLegend& Legend() const { return legend_; }
+
+  private:
+    SemanticTokensLegend legend_;
+};
+
+/// NegotiateSemanticTokensLegend returns the legend that a server should advertise to a client.
+/// The returned legend holds the token types and modifiers of @p server that are also listed in
+/// @p client, in the order they appear in @p server.
+/// @param server the full legend supported by the server
+/// @param client the semantic token capabilities of the client
+/// @returns the negotiated legend
+SemanticTokensLegend NegotiateSemanticTokensLegend(const SemanticTokensLegend& server,
+                                                   const SemanticTokensClientCapabilities& client);
+
+/// FilterSemanticTokens removes the token types and modifiers that are not in @p legend, so that
+/// the tokens can be encoded with EncodeSemanticTokens(). Modifiers that are not in the legend are
+/// removed from the token. Tokens with a type that is not in the legend are dropped, unless
+/// @p fallback_type is provided, in which case the token is given that type.
+/// @param legend the legend to filter the tokens to, usually from NegotiateSemanticTokensLegend()
+/// @param tokens the tokens to filter
+/// @param fallback_type the optional token type given to tokens with a type that is not in the
+/// legend
+/// @returns the filtered tokens, or a Failure if @p fallback_type is not in the legend.
+Result<std::vector<SemanticToken>> FilterSemanticTokens(const SemanticTokensLegend& legend,
+                                                        std::vector<SemanticToken> tokens,
+                                                        const Optional<String>& fallback_type = {});
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_SEMANTIC_TOKENS_H_
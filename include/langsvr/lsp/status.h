@@ -0,0 +1,90 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_STATUS_H_
+#define LANGSVR_LSP_STATUS_H_
+
+#include <string_view>
+#include <utility>
+
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// StatusParams are the parameters of a StatusNotification
+struct StatusParams {
+    /// The kind of status, such as 'indexing' or 'idle'. The kinds are defined by the server.
+    String kind{};
+
+    /// A human readable description of the status
+    String message{};
+};
+
+/// StatusNotification is the '$/status' notification, sent by a server to inform the client of its
+/// status, such as the progress of background indexing.
+///
+/// Note: '$/status' is a de facto extension used by some servers, and is not part of the Language
+/// Server Protocol. Clients that do not support it are expected to ignore it, as the protocol
+/// allows for notifications whose method starts with '$/'.
+struct StatusNotification : StatusParams {
+    /// The LSP message type
+    static constexpr MessageKind kMessageKind = MessageKind::kNotification;
+
+    /// The LSP name for the notification
+    static constexpr std::string_view kMethod = "$/status";
+
+    /// The direction in which the notification is sent
+    static constexpr MessageDirection kMessageDirection = MessageDirection::kServerToClient;
+
+    /// Does the Notification take parameters?
+    static constexpr bool kHasParams = true;
+
+    /// Is the notification a proposed feature, that is not yet part of a stable LSP release?
+    static constexpr bool kProposed = false;
+};
+
+Result<SuccessType> Decode(const json::Value& v, StatusParams& out);
+Result<const json::Value*> Encode(const StatusParams& in, json::Builder& b);
+
+/// SendStatus sends a '$/status' notification with the status @p kind and the description
+/// @p message, using the session @p session.
+/// @param session the Session or ServerSession used to send the notification
+/// @param kind the kind of status
+/// @param message the human readable description of the status
+/// @returns success or failure
+template <typename SESSION>
+Result<SuccessType> SendStatus(SESSION& session, String kind, String message) {
+    StatusNotification notification;
+    notification.kind = std::move(kind);
+    notification.message = std::move(message);
+    return session.Send(std::move(notification));
+}
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_STATUS_H_
@@ -0,0 +1,69 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_SYMBOLS_H_
+#define LANGSVR_LSP_SYMBOLS_H_
+
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+
+namespace langsvr::lsp {
+
+/// MapSymbolKind maps the symbol kind @p kind to the nearest kind in @p supported. The kinds added
+/// after the initial version of the protocol fall back to their nearest equivalent from 'File' to
+/// 'Array', for example 'Struct' maps to 'Class' and 'EnumMember' maps to 'Constant'. If no
+/// alternative is supported, @p kind is returned unchanged.
+/// @param kind the symbol kind
+/// @param supported the symbol kinds supported by the client
+/// @returns @p kind if it is supported, otherwise the nearest supported kind
+SymbolKind MapSymbolKind(SymbolKind kind, const std::vector<SymbolKind>& supported);
+
+/// MapSymbolKinds maps the kind of each of the document symbols @p symbols, and of their children,
+/// with MapSymbolKind(), using the kinds supported by a client with the symbol kind capabilities
+/// @p capabilities. As in the protocol, a client that does not declare 'symbolKind.valueSet' only
+/// supports the kinds from 'File' to 'Array'.
+/// @param symbols the document symbols
+/// @param capabilities the client's 'textDocument.documentSymbol.symbolKind' capabilities, if any
+/// @returns the document symbols with their kinds mapped
+std::vector<DocumentSymbol> MapSymbolKinds(std::vector<DocumentSymbol> symbols,
+                                           const Optional<ClientSymbolKindOptions>& capabilities);
+
+/// MapSymbolKinds maps the kind of each of the symbols @p symbols.
+/// @see MapSymbolKinds(std::vector<DocumentSymbol>, const Optional<ClientSymbolKindOptions>&)
+std::vector<SymbolInformation> MapSymbolKinds(
+    std::vector<SymbolInformation> symbols,
+    const Optional<ClientSymbolKindOptions>& capabilities);
+
+/// MapSymbolKinds maps the kind of each of the workspace symbols @p symbols.
+/// @see MapSymbolKinds(std::vector<DocumentSymbol>, const Optional<ClientSymbolKindOptions>&)
+std::vector<WorkspaceSymbol> MapSymbolKinds(std::vector<WorkspaceSymbol> symbols,
+                                            const Optional<ClientSymbolKindOptions>& capabilities);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_SYMBOLS_H_
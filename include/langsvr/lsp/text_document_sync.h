@@ -0,0 +1,102 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#ifndef LANGSVR_LSP_TEXT_DOCUMENT_SYNC_H_
+#define LANGSVR_LSP_TEXT_DOCUMENT_SYNC_H_
+
+#include <functional>
+#include <string>
+#include <string_view>
+#include <vector>
+
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/result.h"
+
+namespace langsvr::lsp {
+
+/// OptimalTextDocumentSync returns the incremental content changes that transform the document
+/// text @p original into @p revised. Changed lines are found with a line diff, and each changed
+/// region is narrowed to the characters that differ, so only the modified text is sent.
+/// The changes are ordered from the end of the document to the start, so that each change's range
+/// refers to positions in @p original, and can be applied in order as required by
+/// 'textDocument/didChange'.
+/// @param original the current document text
+/// @param revised the new document text
+/// @param encoding the negotiated position encoding used for the change ranges
+/// @returns the list of changes, which is empty if the texts are equal
+std::vector<TextDocumentContentChangePartial> OptimalTextDocumentSync(
+    std::string_view original,
+    std::string_view revised,
+    PositionEncodingKind encoding = PositionEncodingKind::kUTF16);
+
+/// SafeApplyTextEdits returns the document text @p content with the text edits @p edits applied.
+/// As in the protocol, the ranges of all the edits refer to positions in @p content, and inserts at
+/// the same position are applied in the order of @p edits. Positions beyond the end of a line or
+/// the document are clamped to the end of the line or document.
+/// The protocol requires that the edits do not overlap, but some servers produce overlapping edits
+/// that agree on the result, such as the same edit more than once. The edits are sorted and
+/// adjacent edits are merged. An edit nested in another is merged with it if applying the inner
+/// edit gives the same text as the outer edit, which wins. Any other overlapping edits result in a
+/// Failure, instead of corrupted text.
+/// @param content the document text
+/// @param edits the text edits to apply, in any order
+/// @param encoding the negotiated position encoding used for the edit ranges
+/// @returns the edited document text, or a Failure if a range is inverted or two edits overlap
+/// and cannot be merged
+Result<std::string> SafeApplyTextEdits(
+    std::string_view content,
+    const std::vector<TextEdit>& edits,
+    PositionEncodingKind encoding = PositionEncodingKind::kUTF16);
+
+/// WordPredicate is a function that returns true if the code point is part of a word
+using WordPredicate = std::function<bool(char32_t)>;
+
+/// IsIdentifierCodePoint is the default WordPredicate used by WordRangeAt(). It approximates the
+/// Unicode identifier rules: ASCII letters, digits and '_' are identifier characters, as are all
+/// other code points above U+007F except for whitespace, punctuation and symbols.
+/// @param c the code point
+/// @returns true if @p c can be part of an identifier
+bool IsIdentifierCodePoint(char32_t c);
+
+/// WordRangeAt returns the range of the word at the position @p position in the document text
+/// @p content. The word holds the code point at @p position, or the code point before it if
+/// @p position is at the end of a word, extended in both directions with the code points that
+/// satisfy @p is_word. Words do not span lines.
+/// @param content the document text
+/// @param position the position in the document
+/// @param encoding the negotiated position encoding used for @p position and the returned range
+/// @param is_word the function used to detect the code points of a word
+/// @returns the range of the word, an unset Optional if @p position is not on a word, or a Failure
+/// if @p position is not a valid position in @p content
+Result<Optional<Range>> WordRangeAt(std::string_view content,
+                                    const Position& position,
+                                    PositionEncodingKind encoding = PositionEncodingKind::kUTF16,
+                                    const WordPredicate& is_word = IsIdentifierCodePoint);
+
+}  // namespace langsvr::lsp
+
+#endif  // LANGSVR_LSP_TEXT_DOCUMENT_SYNC_H_
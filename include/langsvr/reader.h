@@ -28,6 +28,7 @@
 #ifndef LANGSVR_READER_H_
 #define LANGSVR_READER_H_
 
+#include <algorithm>
 #include <cstdint>
 #include <string>
 
@@ -56,10 +57,17 @@ class Reader {
     /// @return the deserialized string
     Result<std::string> String(size_t len) {
         static_assert(sizeof(std::byte) == sizeof(char), "length needs calculation");
+        // Read in chunks, so that a bogus length does not allocate the full length up front.
+        static constexpr size_t kChunkSize = 64 * 1024;
         std::string out;
-        out.resize(len);
-        if (size_t n = Read(reinterpret_cast<std::byte*>(out.data()), len); n != len) {
-            return Failure{"EOF"};
+        while (out.size() < len) {
+            size_t offset = out.size();
+            size_t count = std::min(len - offset, kChunkSize);
+            out.resize(offset + count);
+            if (size_t n = Read(reinterpret_cast<std::byte*>(out.data() + offset), count);
+                n != count) {
+                return Failure{"EOF"};
+            }
         }
         return out;
     }
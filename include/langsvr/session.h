@@ -36,29 +36,108 @@
 
 #include "langsvr/json/builder.h"
 #include "langsvr/json/value.h"
+#include "langsvr/lsp/message_direction.h"
 #include "langsvr/lsp/message_kind.h"
 #include "langsvr/result.h"
 
+// Forward declarations
+namespace langsvr::lsp {
+template <typename DATA>
+struct ResponseError;
+}  // namespace langsvr::lsp
+
 namespace langsvr {
 
+/// kIsResponseErrorResult is true if T is a Result with a lsp::ResponseError failure type
+template <typename T>
+static constexpr bool kIsResponseErrorResult = false;
+
+/// kIsResponseErrorResult specialization for Results with a lsp::ResponseError failure type
+template <typename SUCCESS, typename DATA>
+static constexpr bool kIsResponseErrorResult<Result<SUCCESS, lsp::ResponseError<DATA>>> = true;
+
+/// kHasErrorData is true if the request type REQUEST declares an ErrorData type
+template <typename REQUEST>
+static constexpr bool kHasErrorData = requires { typename REQUEST::ErrorData; };
+
+/// ErrorDataResult is the Result type returned by a handler of REQUEST that responds with a
+/// lsp::ResponseError on failure
+template <typename REQUEST>
+using ErrorDataResult =
+    Result<typename REQUEST::Result, lsp::ResponseError<typename REQUEST::ErrorData>>;
+
 /// Session provides a message dispatch registry for LSP messages.
 class Session {
+    /// RequestResponse is the encoded response to a request. One of result or error is set.
+    struct RequestResponse {
+        const json::Value* result = nullptr;
+        const json::Value* error = nullptr;
+    };
     struct RequestHandler {
-        std::function<Result<const json::Value*>(const json::Value&, json::Builder&)> function;
+        std::function<Result<RequestResponse>(const json::Value&, json::Builder&)> function;
         std::function<void()> post_send;
+        lsp::MessageDirection direction = lsp::MessageDirection::kBoth;
+        bool proposed = false;
     };
     struct NotificationHandler {
         std::function<Result<SuccessType>(const json::Value&)> function;
+        lsp::MessageDirection direction = lsp::MessageDirection::kBoth;
+        bool proposed = false;
     };
 
   public:
     using Sender = std::function<Result<SuccessType>(std::string_view)>;
+    using WarningHandler = std::function<void(std::string_view)>;
+
+    /// Role is the end of the connection that the Session represents.
+    enum class Role {
+        /// The session is the language server, and receives client-to-server messages.
+        kServer,
+        /// The session is the language client, and receives server-to-client messages.
+        kClient,
+    };
 
     /// SetSender sets the message send handler used by Session for sending request responses and
     /// notifications.
     /// @param sender the new sender for the session.
     void SetSender(Sender&& sender) { sender_ = std::move(sender); }
 
+    /// SetRole sets the end of the connection that the session represents. Receive() uses the role
+    /// to detect messages that are sent in the wrong direction, such as a
+    /// 'textDocument/publishDiagnostics' notification received by a server.
+    /// The default role is Role::kServer.
+    /// @param role the new role for the session.
+    void SetRole(Role role) { role_ = role; }
+
+    /// SetPermissiveMessageDirection controls how Receive() handles a message that is sent in the
+    /// wrong direction for the session's role. By default the message is rejected as if there were
+    /// no handler registered for the method, and a request is answered with a MethodNotFound
+    /// (-32601) response error, so that the peer is not left waiting for a response. If
+    /// @p permissive is true, the message is dispatched
+    /// to the registered handler anyway, which can be used to work with clients that send slightly
+    /// off-spec traffic. In both cases the message is reported to the WarningHandler.
+    /// @param permissive true to dispatch messages sent in the wrong direction.
+    void SetPermissiveMessageDirection(bool permissive) { permissive_direction_ = permissive; }
+
+    /// SetAllowProposedFeatures controls whether the session warns about handlers registered for
+    /// proposed LSP features, which are not yet part of a stable LSP release and may change. By
+    /// default, the first call to Receive() reports each of these handlers to the WarningHandler.
+    /// @param allow true to opt in to proposed features without warnings.
+    void SetAllowProposedFeatures(bool allow) { allow_proposed_ = allow; }
+
+    /// SetRecoverFromExceptions controls how Receive() handles an exception thrown by a message
+    /// handler. By default the exception propagates out of Receive(). If @p recover is true, the
+    /// exception is caught and reported to the WarningHandler along with a unique correlation id.
+    /// A request is answered with an InternalError (-32603) response error, with a message holding
+    /// only the correlation id. The user can quote the id when they report the problem, and the
+    /// developer can find the details in the logs. Has no effect if exceptions are disabled.
+    /// @param recover true to convert handler exceptions to failures.
+    void SetRecoverFromExceptions(bool recover) { recover_from_exceptions_ = recover; }
+
+    /// SetWarningHandler sets the handler used by Session to report non-fatal protocol issues.
+    /// @param handler the new warning handler for the session.
+    void SetWarningHandler(WarningHandler&& handler) { warning_handler_ = std::move(handler); }
+
     /// Receive decodes the LSP message from the JSON string @p json, calling the appropriate
     /// registered message handler, and sending the response to the registered Sender if the message
     /// was an LSP request.
@@ -112,7 +191,9 @@ class Session {
     /// Register registers the LSP Request or Notification handler to be called when Receive() is
     /// called with a message of the appropriate type.
     /// @tparam F a function with the signature `Result<RESPONSE>(const REQUEST&)` or
-    /// `Result<SuccessType>(const NOTIFICATION&)`
+    /// `Result<SuccessType>(const NOTIFICATION&)`. Handlers of requests with an ErrorData type may
+    /// instead return `Result<RESPONSE, lsp::ResponseError<REQUEST::ErrorData>>`, to respond with a
+    /// structured error.
     /// @return a RegisteredRequestHandler if the parameter type of F is a LSP request, otherwise
     /// void.
     template <typename F>
@@ -133,9 +214,11 @@ class Session {
 
         if constexpr (kIsRequest) {
             auto& handler = request_handlers_[method];
+            handler.direction = Message::kMessageDirection;
+            handler.proposed = IsProposed<Message>();
             handler.function = [f = std::move(callback)](
                                    const json::Value& object,
-                                   json::Builder& json_builder) -> Result<const json::Value*> {
+                                   json::Builder& json_builder) -> Result<RequestResponse> {
                 Message request;
                 if constexpr (Message::kHasParams) {
                     auto params = object.Get("params");
@@ -146,16 +229,35 @@ class Session {
                         return res.Failure();
                     }
                 }
-                // TODO(bclayton): Support Message::ErrorData failure types.
-                Result<typename Message::Result> res = f(request);
-                if (res != Success) {
-                    return res.Failure();
+                using HandlerResult = std::decay_t<decltype(f(request))>;
+                if constexpr (kIsResponseErrorResult<HandlerResult>) {
+                    static_assert(
+                        kHasErrorData<Message>,
+                        "only requests with an ErrorData type can return a ResponseError");
+                    static_assert(std::is_same_v<HandlerResult, ErrorDataResult<Message>>,
+                                  "the ResponseError data type must be the request's ErrorData");
+                    HandlerResult res = f(request);
+                    if (res != Success) {
+                        auto error = Encode(res.Failure(), json_builder);
+                        if (error != Success) {
+                            return error.Failure();
+                        }
+                        return RequestResponse{nullptr, error.Get()};
+                    }
+                    return EncodeResult(res.Get(), json_builder);
+                } else {
+                    Result<typename Message::Result> res = f(request);
+                    if (res != Success) {
+                        return res.Failure();
+                    }
+                    return EncodeResult(res.Get(), json_builder);
                 }
-                return Encode(res.Get(), json_builder);
             };
             return RegisteredRequestHandler{handler};
         } else if constexpr (kIsNotification) {
             auto& handler = notification_handlers_[method];
+            handler.direction = Message::kMessageDirection;
+            handler.proposed = IsProposed<Message>();
             handler.function =
                 [f = std::move(callback)](const json::Value& object) -> Result<SuccessType> {
                 Message notification;
@@ -177,11 +279,111 @@ class Session {
   private:
     Result<SuccessType> SendJson(std::string_view msg);
 
+    /// EncodeResult encodes the request handler's result @p result as a RequestResponse
+    template <typename T>
+    static Result<RequestResponse> EncodeResult(const T& result, json::Builder& json_builder) {
+        auto encoded = Encode(result, json_builder);
+        if (encoded != Success) {
+            return encoded.Failure();
+        }
+        return RequestResponse{encoded.Get(), nullptr};
+    }
+
+    /// @returns true if MESSAGE is a proposed LSP feature
+    template <typename MESSAGE>
+    static constexpr bool IsProposed() {
+        if constexpr (requires { MESSAGE::kProposed; }) {
+            return MESSAGE::kProposed;
+        } else {
+            return false;
+        }
+    }
+
+    /// WarnProposedHandlers reports each handler registered for a proposed LSP feature to the
+    /// WarningHandler.
+    void WarnProposedHandlers();
+
+    /// Recovered reports the exception @p what thrown by the handler for @p method to the
+    /// WarningHandler.
+    /// @returns the error message holding the correlation id, to return in place of the handler's
+    /// result
+    std::string Recovered(std::string_view method, std::string_view what);
+
+    /// CheckDirection checks that a message with the given @p method and @p direction can be
+    /// received by a session with the current role.
+    /// @returns a failure if the message is sent in the wrong direction, and the session is not
+    /// permissive.
+    Result<SuccessType> CheckDirection(std::string_view method, lsp::MessageDirection direction);
+
     Sender sender_;
+    WarningHandler warning_handler_;
+    Role role_ = Role::kServer;
+    bool permissive_direction_ = false;
+    bool allow_proposed_ = false;
+    bool recover_from_exceptions_ = false;
+    bool checked_proposed_ = false;
     std::unordered_map<std::string, RequestHandler> request_handlers_;
     std::unordered_map<std::string, NotificationHandler> notification_handlers_;
 };
 
+/// TypedSession is a Session with a role that is fixed at compile time.
+/// Register() only accepts handlers for messages that can be received by the role, and Send() only
+/// accepts messages that can be sent by the role. Using a message in the wrong direction fails to
+/// compile, instead of failing in Receive().
+template <Session::Role ROLE>
+class TypedSession : private Session {
+    /// True if the session is the language server
+    static constexpr bool kIsServer = ROLE == Session::Role::kServer;
+
+    /// True if a message of type MESSAGE can be sent by the peer to this session
+    template <typename MESSAGE>
+    static constexpr bool kCanReceive = kIsServer
+                                            ? lsp::IsSentByClient(MESSAGE::kMessageDirection)
+                                            : lsp::IsSentByServer(MESSAGE::kMessageDirection);
+
+    /// True if a message of type MESSAGE can be sent by this session to the peer
+    template <typename MESSAGE>
+    static constexpr bool kCanSend = kIsServer ? lsp::IsSentByServer(MESSAGE::kMessageDirection)
+                                               : lsp::IsSentByClient(MESSAGE::kMessageDirection);
+
+  public:
+    /// Constructor
+    TypedSession() { Session::SetRole(ROLE); }
+
+    using Session::Receive;
+    using Session::Sender;
+    using Session::SetAllowProposedFeatures;
+    using Session::SetRecoverFromExceptions;
+    using Session::SetSender;
+    using Session::SetWarningHandler;
+    using Session::WarningHandler;
+
+    /// Register registers the LSP Request or Notification handler to be called when Receive() is
+    /// called with a message of the appropriate type. The message must be one that can be received
+    /// by the session's role.
+    /// @see Session::Register()
+    template <typename F>
+        requires(kCanReceive<ParameterType<F, 0>>)
+    auto Register(F&& callback) {
+        return Session::Register(std::forward<F>(callback));
+    }
+
+    /// Send encodes and sends the LSP request or notification to the Sender registered with
+    /// SetSender. The message must be one that can be sent by the session's role.
+    /// @see Session::Send()
+    template <typename T>
+        requires(kCanSend<std::decay_t<T>>)
+    Result<SuccessType> Send(T&& message) {
+        return Session::Send(std::forward<T>(message));
+    }
+};
+
+/// ServerSession is the TypedSession for a language server
+using ServerSession = TypedSession<Session::Role::kServer>;
+
+/// ClientSession is the TypedSession for a language client
+using ClientSession = TypedSession<Session::Role::kClient>;
+
 }  // namespace langsvr
 
 #endif  // LANGSVR_SESSION_H_
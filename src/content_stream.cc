@@ -27,6 +27,10 @@
 
 #include "langsvr/content_stream.h"
 
+#include <algorithm>
+#include <cctype>
+#include <limits>
+#include <optional>
 #include <sstream>
 #include <string>
 
@@ -37,49 +41,206 @@
 namespace langsvr {
 
 namespace {
-static constexpr std::string_view kContentLength = "Content-Length: ";
+static constexpr std::string_view kContentLength = "Content-Length";
+static constexpr std::string_view kContentType = "Content-Type";
+static constexpr std::string_view kJsonRpcMediaType = "application/vscode-jsonrpc";
 
-Result<SuccessType> Match(Reader& reader, std::string_view str) {
-    auto got = reader.String(str.size());
-    if (got != Success) {
-        return got.Failure();
-    }
-    if (got.Get() != str) {
-        std::stringstream err;
-        err << "expected '" << str << "' got '" << got.Get() << "'";
-        return Failure{err.str()};
+/// HeaderReader reads the bytes of a content header from a Reader, first returning any bytes
+/// that were pushed back after being consumed by a look-ahead.
+class HeaderReader {
+  public:
+    explicit HeaderReader(Reader& reader) : reader_(reader) {}
+
+    /// @returns the next byte of the header, or a failure if the end of the stream was reached.
+    Result<char> Next() {
+        if (!pending_.empty()) {
+            char c = pending_.front();
+            pending_.erase(0, 1);
+            return c;
+        }
+        char c = 0;
+        if (auto read = reader_.Read(reinterpret_cast<std::byte*>(&c), sizeof(c));
+            read != sizeof(c)) {
+            return Failure{"EOF"};
+        }
+        return c;
     }
-    return Success;
+
+    /// Reads a string of @p len bytes from the underlying reader.
+    /// Must only be called once all the pushed back bytes have been returned by Next().
+    Result<std::string> String(size_t len) { return reader_.String(len); }
+
+    /// PushBack queues @p str to be returned by subsequent calls to Next().
+    void PushBack(std::string_view str) { pending_ += str; }
+
+  private:
+    Reader& reader_;
+    std::string pending_;
+};
+
+/// @returns true if @p c can be used in a header field name
+bool IsHeaderNameChar(char c) {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-';
 }
 
-}  // namespace
+/// @returns true if the header field names @p a and @p b are equal. Field names are
+/// case-insensitive.
+bool HeaderNameEquals(std::string_view a, std::string_view b) {
+    return std::equal(a.begin(), a.end(), b.begin(), b.end(), [](char x, char y) {
+        return std::tolower(static_cast<unsigned char>(x)) ==
+               std::tolower(static_cast<unsigned char>(y));
+    });
+}
 
-Result<std::string> ReadContent(Reader& reader) {
-    if (auto match = Match(reader, kContentLength); match != Success) {
-        return match.Failure();
+/// ParseContentLength parses the value of a Content-Length header, consuming the terminating
+/// '\r'.
+/// @param first the first character of the value
+Result<uint64_t> ParseContentLength(HeaderReader& reader, char first) {
+    if (first == '\r') {
+        return Failure{"missing content length value"};
     }
     uint64_t len = 0;
-    while (true) {
-        char c = 0;
-        if (auto read = reader.Read(reinterpret_cast<std::byte*>(&c), sizeof(c));
-            read != sizeof(c)) {
+    for (char c = first;;) {
+        if (c >= '0' && c <= '9') {
+            auto digit = static_cast<uint64_t>(c - '0');
+            if (len > (std::numeric_limits<uint64_t>::max() - digit) / 10) {
+                return Failure{"content length value overflows"};
+            }
+            len = len * 10 + digit;
+        } else if (c == '\r') {
+            return len;
+        } else {
+            return Failure{"invalid content length value"};
+        }
+        auto next = reader.Next();
+        if (next != Success) {
             return Failure{"end of stream while parsing content length"};
         }
-        if (c >= '0' && c <= '9') {
-            len = len * 10 + static_cast<uint64_t>(c - '0');
-            continue;
+        c = next.Get();
+    }
+}
+
+/// ReadHeaderValue reads the value of a header, up to and including the terminating '\r'.
+/// @param first the first character of the value
+/// @returns the header value, without the terminating '\r'
+Result<std::string> ReadHeaderValue(HeaderReader& reader, char first) {
+    std::string value;
+    for (char c = first; c != '\r';) {
+        if (c == '\n') {
+            return Failure{"unexpected line feed in header value"};
         }
-        if (c == '\r') {
-            break;
+        value += c;
+        auto next = reader.Next();
+        if (next != Success) {
+            return next.Failure();
         }
-        return Failure{"invalid content length value"};
+        c = next.Get();
     }
+    return value;
+}
 
-    auto got = reader.String(3);
-    if (got != Success) {
-        return got.Failure();
+/// @returns @p str with leading and trailing whitespace removed, converted to lower-case
+std::string TrimLower(std::string_view str) {
+    auto is_space = [](char c) { return c == ' ' || c == '\t'; };
+    while (!str.empty() && is_space(str.front())) {
+        str.remove_prefix(1);
     }
-    if (got.Get() != "\n\r\n") {
+    while (!str.empty() && is_space(str.back())) {
+        str.remove_suffix(1);
+    }
+    std::string out(str);
+    std::transform(out.begin(), out.end(), out.begin(),
+                   [](unsigned char c) { return static_cast<char>(std::tolower(c)); });
+    return out;
+}
+
+/// @returns true if @p value is a Content-Type supported by the protocol: the media type
+/// 'application/vscode-jsonrpc' with an optional 'utf-8' charset. 'utf8' is also accepted for
+/// backwards compatibility.
+bool IsValidContentType(std::string_view value) {
+    auto semicolon = value.find(';');
+    if (TrimLower(value.substr(0, semicolon)) != kJsonRpcMediaType) {
+        return false;
+    }
+    while (semicolon != std::string_view::npos) {
+        value = value.substr(semicolon + 1);
+        semicolon = value.find(';');
+        auto param = value.substr(0, semicolon);
+        auto equals = param.find('=');
+        if (equals == std::string_view::npos) {
+            return false;
+        }
+        if (TrimLower(param.substr(0, equals)) == "charset") {
+            auto charset = TrimLower(param.substr(equals + 1));
+            if (charset != "utf-8" && charset != "utf8") {
+                return false;
+            }
+        }
+    }
+    return true;
+}
+
+}  // namespace
+
+Result<std::string> ReadContent(Reader& stream, const ReadContentOptions& options) {
+    HeaderReader reader(stream);
+    std::optional<uint64_t> content_length;
+    std::optional<std::string> content_type;
+
+    while (true) {
+        // Header field name, up to the ':' separator
+        std::string name;
+        while (true) {
+            auto c = reader.Next();
+            if (c != Success) {
+                return c.Failure();
+            }
+            if (c.Get() == ':') {
+                break;
+            }
+            if (!IsHeaderNameChar(c.Get())) {
+                return Failure{"invalid header name"};
+            }
+            name += c.Get();
+        }
+
+        // Header field value, skipping any leading whitespace
+        auto first = reader.Next();
+        while (first == Success && first.Get() == ' ') {
+            first = reader.Next();
+        }
+        if (first != Success) {
+            return Failure{"end of stream while parsing header '" + name + "'"};
+        }
+        if (HeaderNameEquals(name, kContentLength)) {
+            auto len = ParseContentLength(reader, first.Get());
+            if (len != Success) {
+                return len.Failure();
+            }
+            content_length = len.Get();
+        } else {
+            auto value = ReadHeaderValue(reader, first.Get());
+            if (value != Success) {
+                return value.Failure();
+            }
+            if (HeaderNameEquals(name, kContentType)) {
+                content_type = value.Move();
+            }
+        }
+
+        // The header value is terminated by '\r\n', and the header by an additional '\r\n'.
+        auto got = reader.String(3);
+        if (got != Success) {
+            return got.Failure();
+        }
+        if (got.Get() == "\n\r\n") {
+            break;
+        }
+        if (got.Get()[0] == '\n' && IsHeaderNameChar(got.Get()[1])) {
+            reader.PushBack(std::string_view(got.Get()).substr(1));
+            continue;  // Another header field follows
+        }
+
         auto fmt = [](std::string s) {
             s = ReplaceAll(s, "\n", "␊");
             s = ReplaceAll(s, "\r", "␍");
@@ -91,12 +252,31 @@ Result<std::string> ReadContent(Reader& reader) {
         return Failure{err.str()};
     }
 
-    return reader.String(len);
+    if (!content_length) {
+        return Failure{"missing '" + std::string(kContentLength) + "' header"};
+    }
+    if (!content_type) {
+        if (options.warning) {
+            options.warning("missing '" + std::string(kContentType) + "' header");
+        }
+    } else if (!IsValidContentType(*content_type)) {
+        auto msg = "unsupported '" + std::string(kContentType) + "' value '" + *content_type + "'";
+        if (options.strict_content_type) {
+            return Failure{msg};
+        }
+        if (options.warning) {
+            options.warning(msg);
+        }
+    }
+    if (*content_length > std::numeric_limits<size_t>::max()) {
+        return Failure{"content length value overflows"};
+    }
+    return reader.String(*content_length);
 }
 
 Result<SuccessType> WriteContent(Writer& writer, std::string_view content) {
     std::stringstream ss;
-    ss << kContentLength << content.length() << "\r\n\r\n" << content;
+    ss << kContentLength << ": " << content.length() << "\r\n\r\n" << content;
     return writer.String(ss.str());
 }
 
@@ -27,7 +27,10 @@
 
 #include "langsvr/content_stream.h"
 
-#include "gtest/gtest.h"
+#include <string>
+#include <vector>
+
+#include "gmock/gmock.h"
 
 #include "langsvr/buffer_reader.h"
 #include "langsvr/buffer_writer.h"
@@ -95,6 +98,143 @@ TEST(ReadContent, ValidMessages) {
     }
 }
 
+TEST(ReadContent, HeaderEdgeCases) {
+    struct Case {
+        std::string_view name;
+        std::string_view input;
+        // The expected content, if the read should succeed
+        std::string_view content;
+        // The expected failure reason, if the read should fail
+        std::string_view failure;
+    };
+
+    static constexpr std::string_view kContentType =
+        "Content-Type: application/vscode-jsonrpc; charset=utf-8";
+
+    const std::string content_type_after =
+        "Content-Length: 5\r\n" + std::string(kContentType) + "\r\n\r\nhello";
+    const std::string content_type_before =
+        std::string(kContentType) + "\r\nContent-Length: 5\r\n\r\nhello";
+    const std::string content_type_only = std::string(kContentType) + "\r\n\r\nhello";
+
+    const Case cases[] = {
+        {"ContentTypeAfterLength", content_type_after, "hello", ""},
+        {"ContentTypeBeforeLength", content_type_before, "hello", ""},
+        {"UnknownHeader", "X-Custom: 1\r\nContent-Length: 5\r\n\r\nhello", "hello", ""},
+        {"NoSpaceAfterColon", "Content-Length:5\r\n\r\nhello", "hello", ""},
+        {"LeadingZeros", "Content-Length: 005\r\n\r\nhello", "hello", ""},
+        {"LowerCaseName", "content-length: 5\r\n\r\nhello", "hello", ""},
+        {"UpperCaseName", "CONTENT-LENGTH: 5\r\n\r\nhello", "hello", ""},
+        {"ZeroLength", "Content-Length: 0\r\n\r\n", "", ""},
+        {"ZeroLengthThenMessage", "Content-Length: 0\r\n\r\nhello", "", ""},
+        {"MissingContentLength", content_type_only, "", "missing 'Content-Length' header"},
+        {"UnixLineEndings", "Content-Length: 5\n\nhello", "", "invalid content length value"},
+        {"MixedLineEndings", "Content-Length: 5\r\n\nhello", "",
+         "expected '␍␊␍␊' got '␍␊␊h'"},
+        {"UnixLineEndingsAfterContentType", "Content-Length: 5\r\nContent-Type: x\n\nhello", "",
+         "unexpected line feed in header value"},
+        {"InvalidHeaderName", "Content Length: 5\r\n\r\nhello", "", "invalid header name"},
+        {"EmptyLength", "Content-Length: \r\n\r\nhello", "", "missing content length value"},
+        {"EmptyLengthNoSpace", "Content-Length:\r\n\r\n", "", "missing content length value"},
+        {"NegativeLength", "Content-Length: -1\r\n\r\nhello", "", "invalid content length value"},
+        {"LengthGreaterThanInt32Max", "Content-Length: 2147483648\r\n\r\nhello", "", "EOF"},
+        {"LengthGreaterThanUint32Max", "Content-Length: 4294967296\r\n\r\nhello", "", "EOF"},
+        {"LengthUint64Max", "Content-Length: 18446744073709551615\r\n\r\nhello", "", "EOF"},
+        {"LengthOverflowsUint64", "Content-Length: 18446744073709551616\r\n\r\nhello", "",
+         "content length value overflows"},
+        {"EndOfStreamInLength", "Content-Length: 12", "",
+         "end of stream while parsing content length"},
+        {"EndOfStreamInHeaderName", "Content-Len", "", "EOF"},
+    };
+
+    for (auto& c : cases) {
+        SCOPED_TRACE(c.name);
+        BufferReader reader(c.input);
+        auto got = ReadContent(reader);
+        if (c.failure.empty()) {
+            ASSERT_EQ(got, Success);
+            EXPECT_EQ(got.Get(), c.content);
+        } else {
+            ASSERT_NE(got, Success);
+            EXPECT_EQ(got.Failure().reason, c.failure);
+        }
+    }
+}
+
+TEST(ReadContent, ContentType) {
+    struct Case {
+        std::string_view name;
+        // The Content-Type header value, or empty to omit the header
+        std::string_view content_type;
+        bool strict;
+        // The expected failure reason, if the read should fail
+        std::string_view failure;
+        // The expected warning, if any
+        std::string_view warning;
+    };
+
+    const Case cases[] = {
+        {"Valid", "application/vscode-jsonrpc; charset=utf-8", true, "", ""},
+        {"ValidUTF8", "application/vscode-jsonrpc; charset=utf8", true, "", ""},
+        {"ValidCaseInsensitive", "Application/VSCode-JSONRPC;charset=UTF-8", true, "", ""},
+        {"ValidNoCharset", "application/vscode-jsonrpc", true, "", ""},
+        {"Missing", "", true, "", "missing 'Content-Type' header"},
+        {"WrongCharsetStrict", "application/vscode-jsonrpc; charset=latin1", true,
+         "unsupported 'Content-Type' value 'application/vscode-jsonrpc; charset=latin1'", ""},
+        {"WrongCharsetPermissive", "application/vscode-jsonrpc; charset=latin1", false, "",
+         "unsupported 'Content-Type' value 'application/vscode-jsonrpc; charset=latin1'"},
+        {"WrongMediaTypeStrict", "text/plain", true,
+         "unsupported 'Content-Type' value 'text/plain'", ""},
+        {"WrongMediaTypePermissive", "text/plain", false, "",
+         "unsupported 'Content-Type' value 'text/plain'"},
+    };
+
+    for (auto& c : cases) {
+        SCOPED_TRACE(c.name);
+        std::string input = "Content-Length: 5\r\n";
+        if (!c.content_type.empty()) {
+            input += "Content-Type: " + std::string(c.content_type) + "\r\n";
+        }
+        input += "\r\nhello";
+
+        std::vector<std::string> warnings;
+        ReadContentOptions options;
+        options.strict_content_type = c.strict;
+        options.warning = [&](std::string_view msg) { warnings.push_back(std::string(msg)); };
+
+        BufferReader reader(input);
+        auto got = ReadContent(reader, options);
+        if (c.failure.empty()) {
+            ASSERT_EQ(got, Success);
+            EXPECT_EQ(got.Get(), "hello");
+        } else {
+            ASSERT_NE(got, Success);
+            EXPECT_EQ(got.Failure().reason, c.failure);
+        }
+        if (c.warning.empty()) {
+            EXPECT_THAT(warnings, testing::IsEmpty());
+        } else {
+            EXPECT_THAT(warnings, testing::ElementsAre(c.warning));
+        }
+    }
+}
+
+TEST(ReadContent, ContentTypeNameCaseInsensitive) {
+    ReadContentOptions options;
+    options.strict_content_type = true;
+
+    BufferReader valid(
+        "content-type: application/vscode-jsonrpc\r\ncontent-length: 5\r\n\r\nhello");
+    auto got = ReadContent(valid, options);
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "hello");
+
+    BufferReader invalid("CONTENT-TYPE: text/plain\r\nContent-Length: 5\r\n\r\nhello");
+    got = ReadContent(invalid, options);
+    ASSERT_NE(got, Success);
+    EXPECT_EQ(got.Failure().reason, "unsupported 'Content-Type' value 'text/plain'");
+}
+
 TEST(WriteContent, Single) {
     BufferWriter writer;
     auto got = WriteContent(writer, "hello world");
@@ -117,5 +257,40 @@ TEST(WriteContent, Multiple) {
     }
 }
 
+TEST(WriteContent, Empty) {
+    BufferWriter writer;
+    auto got = WriteContent(writer, "");
+    EXPECT_EQ(got, Success);
+    EXPECT_EQ(writer.BufferString(), "Content-Length: 0\r\n\r\n");
+}
+
+TEST(WriteContent, AlwaysCRLF) {
+    // The header must always be terminated with '\r\n', regardless of the platform's line ending.
+    // Content containing line feeds must be written verbatim.
+    BufferWriter writer;
+    auto got = WriteContent(writer, "a\nb\r\nc");
+    EXPECT_EQ(got, Success);
+    EXPECT_EQ(writer.BufferString(), "Content-Length: 6\r\n\r\na\nb\r\nc");
+
+    auto header = writer.BufferString().substr(0, writer.BufferString().find("\r\n\r\n") + 4);
+    for (size_t i = 0; i < header.size(); i++) {
+        if (header[i] == '\n') {
+            EXPECT_TRUE(i > 0 && header[i - 1] == '\r');
+        }
+    }
+}
+
+TEST(WriteContent, ReadBack) {
+    BufferWriter writer;
+    EXPECT_EQ(WriteContent(writer, "hello"), Success);
+    EXPECT_EQ(WriteContent(writer, ""), Success);
+    EXPECT_EQ(WriteContent(writer, "line\nfeed"), Success);
+
+    BufferReader reader(writer.BufferString());
+    EXPECT_EQ(ReadContent(reader), "hello");
+    EXPECT_EQ(ReadContent(reader), "");
+    EXPECT_EQ(ReadContent(reader), "line\nfeed");
+}
+
 }  // namespace
 }  // namespace langsvr
@@ -0,0 +1,88 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/initialization_buffer.h"
+
+#include <utility>
+
+#include "langsvr/json/builder.h"
+
+namespace langsvr {
+
+InitializationBuffer::InitializationBuffer(Receiver&& receiver, size_t max_buffered)
+    : receiver_(std::move(receiver)), max_buffered_(max_buffered) {}
+
+Result<SuccessType> InitializationBuffer::Receive(std::string_view json) {
+    if (initialized_) {
+        return receiver_(json);
+    }
+
+    // Messages that cannot be parsed are passed on, so the receiver reports the error
+    auto json_builder = json::Builder::Create();
+    auto object = json_builder->Parse(json);
+    if (object != Success || object.Get()->Has("id")) {
+        return receiver_(json);
+    }
+    auto method = object.Get()->Get<json::String>("method");
+    if (method != Success) {
+        return receiver_(json);
+    }
+
+    if (method.Get().starts_with("textDocument/did")) {
+        if (max_buffered_ == 0) {
+            return receiver_(json);
+        }
+        if (buffered_.size() >= max_buffered_) {
+            if (warning_handler_) {
+                warning_handler_("initialization buffer is full (" +
+                                 std::to_string(max_buffered_) +
+                                 " notifications). Dropping the oldest notification");
+            }
+            buffered_.pop_front();
+        }
+        buffered_.emplace_back(json);
+        return Success;
+    }
+
+    if (method.Get() != "initialized") {
+        return receiver_(json);
+    }
+
+    auto result = receiver_(json);
+    initialized_ = true;
+    while (!buffered_.empty()) {
+        auto message = std::move(buffered_.front());
+        buffered_.pop_front();
+        if (auto res = receiver_(message); res != Success && result == Success) {
+            result = res.Failure();
+        }
+    }
+    return result;
+}
+
+}  // namespace langsvr
@@ -0,0 +1,156 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/initialization_buffer.h"
+
+#include <string>
+#include <vector>
+
+#include "gmock/gmock.h"
+#include "langsvr/lsp/lsp.h"
+#include "langsvr/session.h"
+
+namespace langsvr {
+namespace {
+
+/// @returns a 'textDocument/didOpen' notification message for the document @p uri
+std::string DidOpen(std::string uri) {
+    return R"({"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{)"
+           R"("uri":")" +
+           uri + R"(","languageId":"cpp","version":1,"text":""}}})";
+}
+
+/// @returns a 'textDocument/didClose' notification message for the document @p uri
+std::string DidClose(std::string uri) {
+    return R"({"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{)"
+           R"("uri":")" +
+           uri + R"("}}})";
+}
+
+static constexpr std::string_view kInitializedMsg =
+    R"({"jsonrpc":"2.0","method":"initialized","params":{}})";
+
+/// Server is a ServerSession that logs the notifications it handles
+struct Server {
+    using InitializedMsg = lsp::InitializedNotification;
+    using DidOpenMsg = lsp::TextDocumentDidOpenNotification;
+    using DidCloseMsg = lsp::TextDocumentDidCloseNotification;
+
+    Server() {
+        session.Register([&](const InitializedMsg&) -> Result<SuccessType> {
+            log.push_back("initialized");
+            return Success;
+        });
+        session.Register([&](const DidOpenMsg& n) -> Result<SuccessType> {
+            log.push_back("open " + n.text_document.uri);
+            return Success;
+        });
+        session.Register([&](const DidCloseMsg& n) -> Result<SuccessType> {
+            log.push_back("close " + n.text_document.uri);
+            return Success;
+        });
+    }
+
+    /// @returns a Receiver that passes messages to the session
+    InitializationBuffer::Receiver Receiver() {
+        return [&](std::string_view msg) { return session.Receive(msg); };
+    }
+
+    ServerSession session;
+    std::vector<std::string> log;
+};
+
+TEST(InitializationBufferTest, ReplaysAfterInitialized) {
+    Server server;
+    InitializationBuffer buffer(server.Receiver());
+
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///a.cc")), Success);
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///b.cc")), Success);
+    EXPECT_EQ(buffer.Receive(DidClose("file:///a.cc")), Success);
+    EXPECT_TRUE(server.log.empty());
+    EXPECT_EQ(buffer.Buffered(), 3u);
+    EXPECT_FALSE(buffer.Initialized());
+
+    EXPECT_EQ(buffer.Receive(kInitializedMsg), Success);
+    EXPECT_TRUE(buffer.Initialized());
+    EXPECT_EQ(buffer.Buffered(), 0u);
+    EXPECT_THAT(server.log, testing::ElementsAre("initialized", "open file:///a.cc",
+                                                 "open file:///b.cc", "close file:///a.cc"));
+
+    // Once initialized, notifications are passed straight through
+    EXPECT_EQ(buffer.Receive(DidClose("file:///b.cc")), Success);
+    EXPECT_EQ(server.log.back(), "close file:///b.cc");
+}
+
+TEST(InitializationBufferTest, PassesThroughOtherMessages) {
+    std::vector<std::string> received;
+    InitializationBuffer buffer([&](std::string_view msg) {
+        received.push_back(std::string(msg));
+        return Success;
+    });
+
+    EXPECT_EQ(buffer.Receive(R"({"jsonrpc":"2.0","method":"$/setTrace","params":{}})"), Success);
+    EXPECT_EQ(buffer.Receive(R"({"jsonrpc":"2.0","id":1,"method":"textDocument/didX"})"), Success);
+    EXPECT_EQ(buffer.Receive("not json"), Success);
+    EXPECT_EQ(received.size(), 3u);
+    EXPECT_EQ(buffer.Buffered(), 0u);
+}
+
+TEST(InitializationBufferTest, DropsOldestWhenFull) {
+    Server server;
+    InitializationBuffer buffer(server.Receiver(), 2);
+    std::vector<std::string> warnings;
+    buffer.SetWarningHandler([&](std::string_view w) { warnings.push_back(std::string(w)); });
+
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///a.cc")), Success);
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///b.cc")), Success);
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///c.cc")), Success);
+    EXPECT_EQ(buffer.Buffered(), 2u);
+    ASSERT_EQ(warnings.size(), 1u);
+    EXPECT_THAT(warnings[0], testing::HasSubstr("Dropping the oldest notification"));
+
+    EXPECT_EQ(buffer.Receive(kInitializedMsg), Success);
+    EXPECT_THAT(server.log,
+                testing::ElementsAre("initialized", "open file:///b.cc", "open file:///c.cc"));
+}
+
+TEST(InitializationBufferTest, ReplayFailure) {
+    Server server;
+    InitializationBuffer buffer(server.Receiver());
+
+    EXPECT_EQ(buffer.Receive(R"({"jsonrpc":"2.0","method":"textDocument/didSave","params":{}})"),
+              Success);
+    EXPECT_EQ(buffer.Receive(DidOpen("file:///a.cc")), Success);
+
+    // 'textDocument/didSave' has no handler, but the remaining notifications are still replayed
+    EXPECT_NE(buffer.Receive(kInitializedMsg), Success);
+    EXPECT_THAT(server.log, testing::ElementsAre("initialized", "open file:///a.cc"));
+}
+
+}  // namespace
+}  // namespace langsvr
@@ -0,0 +1,95 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/lsp/code_actions.h"
+
+#include <algorithm>
+#include <string>
+#include <string_view>
+#include <utility>
+#include <vector>
+
+#include "langsvr/json/builder.h"
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// @returns the LSP string of the code action kind @p kind
+std::string ToString(CodeActionKind kind) {
+    auto b = json::Builder::Create();
+    auto encoded = Encode(kind, *b);
+    return encoded.Get()->String().Get();
+}
+
+}  // namespace
+
+bool IsSubKind(std::string_view child, std::string_view parent) {
+    if (parent.empty()) {
+        return true;
+    }
+    if (!child.starts_with(parent)) {
+        return false;
+    }
+    return child.size() == parent.size() || child[parent.size()] == '.';
+}
+
+bool IsSubKind(CodeActionKind child, CodeActionKind parent) {
+    return IsSubKind(ToString(child), ToString(parent));
+}
+
+std::vector<CodeAction> FilterCodeActionsByKinds(std::vector<CodeAction> actions,
+                                                 const std::vector<CodeActionKind>& only) {
+    if (only.empty()) {
+        return actions;
+    }
+    std::vector<std::string> parents;
+    parents.reserve(only.size());
+    for (auto kind : only) {
+        parents.push_back(ToString(kind));
+    }
+    std::erase_if(actions, [&](const CodeAction& action) {
+        if (!action.kind) {
+            return true;
+        }
+        auto kind = ToString(*action.kind);
+        return std::none_of(parents.begin(), parents.end(),
+                            [&](const std::string& parent) { return IsSubKind(kind, parent); });
+    });
+    return actions;
+}
+
+std::vector<CodeAction> FilterCodeActionsByKinds(std::vector<CodeAction> actions,
+                                                 const CodeActionContext& context) {
+    if (!context.only) {
+        return actions;
+    }
+    return FilterCodeActionsByKinds(std::move(actions), *context.only);
+}
+
+}  // namespace langsvr::lsp
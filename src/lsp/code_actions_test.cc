@@ -0,0 +1,121 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/lsp/code_actions.h"
+
+#include <string>
+#include <utility>
+#include <vector>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+CodeAction Action(std::string title, Optional<CodeActionKind> kind) {
+    CodeAction action;
+    action.title = std::move(title);
+    action.kind = std::move(kind);
+    return action;
+}
+
+std::vector<std::string> Titles(const std::vector<CodeAction>& actions) {
+    std::vector<std::string> titles;
+    for (auto& action : actions) {
+        titles.push_back(action.title);
+    }
+    return titles;
+}
+
+std::vector<CodeAction> Actions() {
+    return {
+        Action("fix", CodeActionKind::kQuickFix),
+        Action("extract", CodeActionKind::kRefactorExtract),
+        Action("inline", CodeActionKind::kRefactorInline),
+        Action("refactor", CodeActionKind::kRefactor),
+        Action("imports", CodeActionKind::kSourceOrganizeImports),
+        Action("untyped", {}),
+    };
+}
+
+TEST(CodeActionsTest, IsSubKindStrings) {
+    EXPECT_TRUE(IsSubKind("refactor", "refactor"));
+    EXPECT_TRUE(IsSubKind("refactor.extract", "refactor"));
+    EXPECT_TRUE(IsSubKind("refactor.extract.function", "refactor"));
+    EXPECT_TRUE(IsSubKind("refactor.extract.function", "refactor.extract"));
+    EXPECT_TRUE(IsSubKind("quickfix", ""));
+    EXPECT_TRUE(IsSubKind("", ""));
+    EXPECT_FALSE(IsSubKind("refactor", "refactor.extract"));
+    EXPECT_FALSE(IsSubKind("refactor.extract", "refactor.ex"));
+    EXPECT_FALSE(IsSubKind("refactoring", "refactor"));
+    EXPECT_FALSE(IsSubKind("source", "quickfix"));
+    EXPECT_FALSE(IsSubKind("", "quickfix"));
+}
+
+TEST(CodeActionsTest, IsSubKindEnums) {
+    EXPECT_TRUE(IsSubKind(CodeActionKind::kRefactorExtract, CodeActionKind::kRefactor));
+    EXPECT_TRUE(IsSubKind(CodeActionKind::kSourceFixAll, CodeActionKind::kSource));
+    EXPECT_TRUE(IsSubKind(CodeActionKind::kQuickFix, CodeActionKind::kEmpty));
+    EXPECT_FALSE(IsSubKind(CodeActionKind::kRefactor, CodeActionKind::kRefactorExtract));
+    EXPECT_FALSE(IsSubKind(CodeActionKind::kSource, CodeActionKind::kQuickFix));
+}
+
+TEST(CodeActionsTest, FilterByParentKind) {
+    auto got = FilterCodeActionsByKinds(Actions(), {CodeActionKind::kRefactor});
+    EXPECT_THAT(Titles(got), testing::ElementsAre("extract", "inline", "refactor"));
+}
+
+TEST(CodeActionsTest, FilterByMultipleKinds) {
+    auto got = FilterCodeActionsByKinds(
+        Actions(), {CodeActionKind::kQuickFix, CodeActionKind::kRefactorInline,
+                    CodeActionKind::kSource});
+    EXPECT_THAT(Titles(got), testing::ElementsAre("fix", "inline", "imports"));
+}
+
+TEST(CodeActionsTest, FilterByEmptyKindDropsUntyped) {
+    auto got = FilterCodeActionsByKinds(Actions(), {CodeActionKind::kEmpty});
+    EXPECT_THAT(Titles(got),
+                testing::ElementsAre("fix", "extract", "inline", "refactor", "imports"));
+}
+
+TEST(CodeActionsTest, FilterWithoutKinds) {
+    auto got = FilterCodeActionsByKinds(Actions(), std::vector<CodeActionKind>{});
+    EXPECT_EQ(got.size(), 6u);
+}
+
+TEST(CodeActionsTest, FilterByContext) {
+    CodeActionContext context;
+    EXPECT_EQ(FilterCodeActionsByKinds(Actions(), context).size(), 6u);
+
+    context.only = std::vector<CodeActionKind>{CodeActionKind::kSourceOrganizeImports};
+    auto got = FilterCodeActionsByKinds(Actions(), context);
+    EXPECT_THAT(Titles(got), testing::ElementsAre("imports"));
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,151 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/completion.h"
+
+#include <algorithm>
+#include <string_view>
+#include <utility>
+#include <vector>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// @returns the kinds to try in place of @p kind, nearest first, before falling back to 'Text'
+std::vector<CompletionItemKind> Alternatives(CompletionItemKind kind) {
+    using K = CompletionItemKind;
+    switch (kind) {
+        case K::kMethod:
+            return {K::kFunction};
+        case K::kFunction:
+            return {K::kMethod};
+        case K::kConstructor:
+            return {K::kMethod, K::kFunction};
+        case K::kField:
+            return {K::kProperty, K::kVariable};
+        case K::kVariable:
+            return {K::kField, K::kProperty};
+        case K::kClass:
+            return {K::kStruct, K::kInterface};
+        case K::kInterface:
+            return {K::kClass};
+        case K::kModule:
+            return {K::kFolder, K::kFile};
+        case K::kProperty:
+            return {K::kField, K::kVariable};
+        case K::kUnit:
+            return {K::kValue};
+        case K::kEnum:
+            return {K::kClass};
+        case K::kColor:
+            return {K::kValue};
+        case K::kReference:
+            return {K::kFile};
+        case K::kFolder:
+            return {K::kFile};
+        case K::kEnumMember:
+            return {K::kConstant, K::kValue};
+        case K::kConstant:
+            return {K::kValue, K::kVariable};
+        case K::kStruct:
+            return {K::kClass};
+        case K::kEvent:
+            return {K::kField, K::kProperty};
+        case K::kTypeParameter:
+            return {K::kClass, K::kInterface};
+        default:
+            return {};
+    }
+}
+
+/// The completion item kinds supported by clients that do not declare a value set
+const std::vector<CompletionItemKind>& DefaultCompletionItemKinds() {
+    static const std::vector<CompletionItemKind> kinds = [] {
+        std::vector<CompletionItemKind> out;
+        for (auto kind = static_cast<int>(CompletionItemKind::kText);
+             kind <= static_cast<int>(CompletionItemKind::kReference); kind++) {
+            out.push_back(static_cast<CompletionItemKind>(kind));
+        }
+        return out;
+    }();
+    return kinds;
+}
+
+}  // namespace
+
+CompletionList TruncateCompletionList(CompletionList list, size_t max_items) {
+    if (list.items.size() <= max_items) {
+        return list;
+    }
+
+    // As in the protocol, an item without a 'sortText' is sorted by its 'label'
+    auto sort_key = [](const CompletionItem& item) -> std::string_view {
+        return item.sort_text ? *item.sort_text : item.label;
+    };
+    std::stable_sort(list.items.begin(), list.items.end(),
+                     [&](const CompletionItem& a, const CompletionItem& b) {
+                         return sort_key(a) < sort_key(b);
+                     });
+    list.items.resize(max_items);
+    list.is_incomplete = true;
+    return list;
+}
+
+CompletionItemKind MapCompletionItemKind(CompletionItemKind kind,
+                                         const std::vector<CompletionItemKind>& supported) {
+    auto is_supported = [&](CompletionItemKind k) {
+        return std::find(supported.begin(), supported.end(), k) != supported.end();
+    };
+    if (is_supported(kind)) {
+        return kind;
+    }
+    for (auto alternative : Alternatives(kind)) {
+        if (is_supported(alternative)) {
+            return alternative;
+        }
+    }
+    return is_supported(CompletionItemKind::kText) ? CompletionItemKind::kText : kind;
+}
+
+std::vector<CompletionItem> MapCompletionItemKinds(
+    std::vector<CompletionItem> items,
+    const Optional<CompletionClientCapabilities>& capabilities) {
+    const auto* supported = &DefaultCompletionItemKinds();
+    if (capabilities && capabilities->completion_item_kind &&
+        capabilities->completion_item_kind->value_set) {
+        supported = &*capabilities->completion_item_kind->value_set;
+    }
+    for (auto& item : items) {
+        if (item.kind) {
+            item.kind = MapCompletionItemKind(*item.kind, *supported);
+        }
+    }
+    return items;
+}
+
+}  // namespace langsvr::lsp
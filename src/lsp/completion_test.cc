@@ -0,0 +1,125 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/completion.h"
+
+#include <string>
+#include <utility>
+#include <vector>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+CompletionItem Item(std::string label, Optional<String> sort_text = {}) {
+    CompletionItem item;
+    item.label = std::move(label);
+    item.sort_text = std::move(sort_text);
+    return item;
+}
+
+std::vector<std::string> Labels(const CompletionList& list) {
+    std::vector<std::string> labels;
+    for (auto& item : list.items) {
+        labels.push_back(item.label);
+    }
+    return labels;
+}
+
+TEST(CompletionTest, TruncateFits) {
+    CompletionList list;
+    list.items = {Item("b"), Item("a")};
+    auto got = TruncateCompletionList(list, 2);
+    EXPECT_FALSE(got.is_incomplete);
+    EXPECT_THAT(Labels(got), testing::ElementsAre("b", "a"));
+}
+
+TEST(CompletionTest, TruncateSortsBySortText) {
+    CompletionList list;
+    list.items = {Item("c"), Item("a", String("z")), Item("b"), Item("d", String("0"))};
+    auto got = TruncateCompletionList(list, 3);
+    EXPECT_TRUE(got.is_incomplete);
+    EXPECT_THAT(Labels(got), testing::ElementsAre("d", "b", "c"));
+}
+
+TEST(CompletionTest, TruncateIsStable) {
+    CompletionList list;
+    list.items = {Item("x", String("1")), Item("y", String("0")), Item("z", String("1"))};
+    auto got = TruncateCompletionList(list, 2);
+    EXPECT_TRUE(got.is_incomplete);
+    EXPECT_THAT(Labels(got), testing::ElementsAre("y", "x"));
+}
+
+TEST(CompletionTest, TruncateToZero) {
+    CompletionList list;
+    list.items = {Item("a")};
+    auto got = TruncateCompletionList(list, 0);
+    EXPECT_TRUE(got.is_incomplete);
+    EXPECT_TRUE(got.items.empty());
+}
+
+TEST(CompletionTest, MapKindSupported) {
+    using K = CompletionItemKind;
+    EXPECT_EQ(MapCompletionItemKind(K::kOperator, {K::kText, K::kOperator}), K::kOperator);
+}
+
+TEST(CompletionTest, MapKindFallback) {
+    using K = CompletionItemKind;
+    std::vector<K> supported{K::kText, K::kFunction, K::kClass, K::kValue, K::kVariable};
+    EXPECT_EQ(MapCompletionItemKind(K::kOperator, supported), K::kText);
+    EXPECT_EQ(MapCompletionItemKind(K::kMethod, supported), K::kFunction);
+    EXPECT_EQ(MapCompletionItemKind(K::kConstructor, supported), K::kFunction);
+    EXPECT_EQ(MapCompletionItemKind(K::kStruct, supported), K::kClass);
+    EXPECT_EQ(MapCompletionItemKind(K::kEnumMember, supported), K::kValue);
+    EXPECT_EQ(MapCompletionItemKind(K::kField, supported), K::kVariable);
+    EXPECT_EQ(MapCompletionItemKind(K::kOperator, {K::kFunction}), K::kOperator);
+}
+
+TEST(CompletionTest, MapKindsWithCapabilities) {
+    using K = CompletionItemKind;
+    std::vector<CompletionItem> items(3);
+    items[0].kind = K::kStruct;
+    items[1].kind = K::kMethod;
+
+    // Without a value set, only 'Text' to 'Reference' are supported
+    auto defaults = MapCompletionItemKinds(items, Optional<CompletionClientCapabilities>{});
+    EXPECT_EQ(defaults[0].kind, K::kClass);
+    EXPECT_EQ(defaults[1].kind, K::kMethod);
+    EXPECT_FALSE(defaults[2].kind);
+
+    CompletionClientCapabilities capabilities;
+    capabilities.completion_item_kind = ClientCompletionItemOptionsKind{};
+    capabilities.completion_item_kind->value_set = std::vector{K::kText, K::kStruct};
+    auto mapped = MapCompletionItemKinds(items, capabilities);
+    EXPECT_EQ(mapped[0].kind, K::kStruct);
+    EXPECT_EQ(mapped[1].kind, K::kText);
+    EXPECT_FALSE(mapped[2].kind);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,152 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/diagnostics.h"
+
+#include <algorithm>
+#include <set>
+#include <string>
+#include <tuple>
+#include <utility>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// DiagnosticKey holds the fields that identify duplicate diagnostics
+using DiagnosticKey = std::tuple<Uinteger,     // range.start.line
+                                 Uinteger,     // range.start.character
+                                 Uinteger,     // range.end.line
+                                 Uinteger,     // range.end.character
+                                 std::string,  // message
+                                 std::string,  // source
+                                 std::string   // code
+                                 >;
+
+/// @returns the DiagnosticKey of @p diagnostic
+DiagnosticKey KeyOf(const Diagnostic& diagnostic) {
+    // Prefix the optional fields, so that an absent field is distinct from an empty string, and
+    // an integer code is distinct from the same number as a string code.
+    std::string source = diagnostic.source ? "s" + *diagnostic.source : "";
+    std::string code;
+    if (diagnostic.code) {
+        if (auto* i = diagnostic.code->Get<Integer>()) {
+            code = "i" + std::to_string(*i);
+        } else if (auto* str = diagnostic.code->Get<String>()) {
+            code = "s" + *str;
+        }
+    }
+    auto& range = diagnostic.range;
+    return {range.start.line, range.start.character, range.end.line, range.end.character,
+            diagnostic.message, std::move(source), std::move(code)};
+}
+
+}  // namespace
+
+std::vector<Diagnostic> StripUnsupportedDiagnosticTags(
+    std::vector<Diagnostic> diagnostics,
+    const std::vector<DiagnosticTag>& supported) {
+    for (auto& diagnostic : diagnostics) {
+        if (!diagnostic.tags) {
+            continue;
+        }
+        std::erase_if(*diagnostic.tags, [&](DiagnosticTag tag) {
+            return std::find(supported.begin(), supported.end(), tag) == supported.end();
+        });
+        if (diagnostic.tags->empty()) {
+            diagnostic.tags.Reset();
+        }
+    }
+    return diagnostics;
+}
+
+std::vector<Diagnostic> StripUnsupportedDiagnosticTags(
+    std::vector<Diagnostic> diagnostics,
+    const Optional<PublishDiagnosticsClientCapabilities>& capabilities) {
+    if (capabilities && capabilities->tag_support) {
+        return StripUnsupportedDiagnosticTags(std::move(diagnostics),
+                                              capabilities->tag_support->value_set);
+    }
+    return StripUnsupportedDiagnosticTags(std::move(diagnostics), std::vector<DiagnosticTag>{});
+}
+
+std::vector<Diagnostic> DeduplicateDiagnostics(std::vector<Diagnostic> diagnostics) {
+    std::set<DiagnosticKey> seen;
+    std::erase_if(diagnostics, [&](const Diagnostic& diagnostic) {
+        return !seen.emplace(KeyOf(diagnostic)).second;
+    });
+    return diagnostics;
+}
+
+std::vector<Diagnostic> MergeDiagnosticSets(const std::vector<std::vector<Diagnostic>>& sets) {
+    std::vector<Diagnostic> merged;
+    for (auto& set : sets) {
+        merged.insert(merged.end(), set.begin(), set.end());
+    }
+    return DeduplicateDiagnostics(std::move(merged));
+}
+
+Optional<String> DiagnosticResultCache::LastResultId(const DocumentUri& uri) const {
+    if (auto it = entries_.find(uri); it != entries_.end()) {
+        return it->second.result_id;
+    }
+    return {};
+}
+
+DocumentDiagnosticReport DiagnosticResultCache::Report(
+    const DocumentDiagnosticParams& params,
+    Optional<std::vector<Diagnostic>> diagnostics) {
+    auto& uri = params.text_document.uri;
+    auto it = entries_.find(uri);
+    if (!diagnostics && it != entries_.end() && params.previous_result_id == it->second.result_id) {
+        RelatedUnchangedDocumentDiagnosticReport unchanged;
+        unchanged.result_id = it->second.result_id;
+        return unchanged;
+    }
+
+    if (it == entries_.end()) {
+        it = entries_.emplace(uri, Entry{}).first;
+        if (!diagnostics) {
+            diagnostics = std::vector<Diagnostic>{};
+        }
+    }
+    if (diagnostics) {
+        it->second.result_id = std::to_string(next_result_id_++);
+        it->second.diagnostics = std::move(*diagnostics);
+    }
+
+    RelatedFullDocumentDiagnosticReport full;
+    full.result_id = it->second.result_id;
+    full.items = it->second.diagnostics;
+    return full;
+}
+
+void DiagnosticResultCache::Forget(const DocumentUri& uri) {
+    entries_.erase(uri);
+}
+
+}  // namespace langsvr::lsp
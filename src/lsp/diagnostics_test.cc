@@ -0,0 +1,221 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/diagnostics.h"
+
+#include <string>
+#include <utility>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+std::vector<Diagnostic> Diagnostics() {
+    std::vector<Diagnostic> diagnostics(4);
+    diagnostics[0].message = "unused and deprecated";
+    diagnostics[0].tags = std::vector{DiagnosticTag::kUnnecessary, DiagnosticTag::kDeprecated};
+    diagnostics[1].message = "deprecated";
+    diagnostics[1].tags = std::vector{DiagnosticTag::kDeprecated};
+    diagnostics[2].message = "no tags";
+    diagnostics[3].message = "empty tags";
+    diagnostics[3].tags = std::vector<DiagnosticTag>{};
+    return diagnostics;
+}
+
+TEST(DiagnosticsTest, StripNone) {
+    auto got = StripUnsupportedDiagnosticTags(
+        Diagnostics(), {DiagnosticTag::kUnnecessary, DiagnosticTag::kDeprecated});
+    ASSERT_EQ(got.size(), 4u);
+    EXPECT_EQ(got[0].tags, (std::vector{DiagnosticTag::kUnnecessary, DiagnosticTag::kDeprecated}));
+    EXPECT_EQ(got[1].tags, (std::vector{DiagnosticTag::kDeprecated}));
+    EXPECT_FALSE(got[2].tags);
+    EXPECT_FALSE(got[3].tags);
+}
+
+TEST(DiagnosticsTest, StripSome) {
+    auto got = StripUnsupportedDiagnosticTags(Diagnostics(), {DiagnosticTag::kUnnecessary});
+    ASSERT_EQ(got.size(), 4u);
+    EXPECT_EQ(got[0].tags, (std::vector{DiagnosticTag::kUnnecessary}));
+    EXPECT_FALSE(got[1].tags);
+    EXPECT_FALSE(got[2].tags);
+    EXPECT_EQ(got[1].message, "deprecated");
+}
+
+TEST(DiagnosticsTest, StripWithCapabilities) {
+    PublishDiagnosticsClientCapabilities capabilities;
+    capabilities.tag_support = ClientDiagnosticsTagOptions{{DiagnosticTag::kDeprecated}};
+
+    auto got = StripUnsupportedDiagnosticTags(Diagnostics(), capabilities);
+    ASSERT_EQ(got.size(), 4u);
+    EXPECT_EQ(got[0].tags, (std::vector{DiagnosticTag::kDeprecated}));
+    EXPECT_EQ(got[1].tags, (std::vector{DiagnosticTag::kDeprecated}));
+}
+
+TEST(DiagnosticsTest, StripWithoutTagSupport) {
+    for (auto capabilities : {Optional<PublishDiagnosticsClientCapabilities>{},
+                              Optional{PublishDiagnosticsClientCapabilities{}}}) {
+        auto got = StripUnsupportedDiagnosticTags(Diagnostics(), capabilities);
+        ASSERT_EQ(got.size(), 4u);
+        for (auto& diagnostic : got) {
+            EXPECT_FALSE(diagnostic.tags);
+        }
+    }
+}
+
+Diagnostic At(Uinteger line, std::string message) {
+    Diagnostic diagnostic;
+    diagnostic.range.start = Position{line, 0};
+    diagnostic.range.end = Position{line, 4};
+    diagnostic.message = std::move(message);
+    return diagnostic;
+}
+
+std::vector<std::string> Messages(const std::vector<Diagnostic>& diagnostics) {
+    std::vector<std::string> messages;
+    for (auto& diagnostic : diagnostics) {
+        messages.push_back(diagnostic.message);
+    }
+    return messages;
+}
+
+TEST(DiagnosticsTest, Deduplicate) {
+    std::vector<Diagnostic> diagnostics{At(1, "a"), At(0, "b"), At(1, "a"), At(2, "a"), At(0, "b")};
+    diagnostics[3].severity = DiagnosticSeverity::kWarning;
+    diagnostics[4].severity = DiagnosticSeverity::kError;
+
+    auto got = DeduplicateDiagnostics(diagnostics);
+    EXPECT_THAT(Messages(got), testing::ElementsAre("a", "b", "a"));
+    EXPECT_EQ(got[0].range.start.line, 1u);
+    EXPECT_EQ(got[1].range.start.line, 0u);
+    EXPECT_FALSE(got[1].severity);  // The first duplicate is kept
+    EXPECT_EQ(got[2].range.start.line, 2u);
+    EXPECT_EQ(diagnostics.size(), 5u);
+}
+
+TEST(DiagnosticsTest, DeduplicateBySourceAndCode) {
+    std::vector<Diagnostic> diagnostics(6, At(0, "msg"));
+    diagnostics[1].source = "lint";
+    diagnostics[2].source = "";
+    diagnostics[3].code = OneOf<Integer, String>{Integer{1}};
+    diagnostics[4].code = OneOf<Integer, String>{String{"1"}};
+    diagnostics[5].code = OneOf<Integer, String>{Integer{1}};
+
+    auto got = DeduplicateDiagnostics(diagnostics);
+    ASSERT_EQ(got.size(), 5u);
+    EXPECT_FALSE(got[0].source);
+    EXPECT_EQ(got[1].source, "lint");
+    EXPECT_EQ(got[2].source, "");
+    EXPECT_TRUE(got[3].code->Is<Integer>());
+    EXPECT_TRUE(got[4].code->Is<String>());
+}
+
+TEST(DiagnosticsTest, MergeDiagnosticSets) {
+    std::vector<Diagnostic> lint{At(0, "unused"), At(3, "style")};
+    std::vector<Diagnostic> types{At(1, "mismatch"), At(0, "unused")};
+    std::vector<Diagnostic> format{At(3, "style"), At(4, "indent")};
+
+    auto got = MergeDiagnosticSets({lint, types, format});
+    EXPECT_THAT(Messages(got), testing::ElementsAre("unused", "style", "mismatch", "indent"));
+    EXPECT_EQ(lint.size(), 2u);
+    EXPECT_EQ(types.size(), 2u);
+    EXPECT_TRUE(MergeDiagnosticSets({}).empty());
+}
+
+DocumentDiagnosticParams Pull(std::string uri, Optional<String> previous_result_id = {}) {
+    DocumentDiagnosticParams params;
+    params.text_document.uri = std::move(uri);
+    params.previous_result_id = std::move(previous_result_id);
+    return params;
+}
+
+TEST(DiagnosticsTest, ResultCacheFullReports) {
+    DiagnosticResultCache cache;
+    EXPECT_FALSE(cache.LastResultId("file:///a.cc"));
+
+    auto first = cache.Report(Pull("file:///a.cc"), std::vector{At(0, "a")});
+    ASSERT_TRUE(first.Is<RelatedFullDocumentDiagnosticReport>());
+    auto* full = first.Get<RelatedFullDocumentDiagnosticReport>();
+    EXPECT_THAT(Messages(full->items), testing::ElementsAre("a"));
+    ASSERT_TRUE(full->result_id);
+    EXPECT_EQ(cache.LastResultId("file:///a.cc"), *full->result_id);
+    String first_id = *full->result_id;
+
+    auto second = cache.Report(Pull("file:///a.cc", first_id), std::vector{At(1, "b")});
+    ASSERT_TRUE(second.Is<RelatedFullDocumentDiagnosticReport>());
+    full = second.Get<RelatedFullDocumentDiagnosticReport>();
+    EXPECT_THAT(Messages(full->items), testing::ElementsAre("b"));
+    EXPECT_NE(full->result_id, first_id);
+    EXPECT_EQ(cache.LastResultId("file:///a.cc"), *full->result_id);
+}
+
+TEST(DiagnosticsTest, ResultCacheUnchanged) {
+    DiagnosticResultCache cache;
+    auto first = cache.Report(Pull("file:///a.cc"), std::vector{At(0, "a")});
+    String id = *first.Get<RelatedFullDocumentDiagnosticReport>()->result_id;
+
+    auto unchanged = cache.Report(Pull("file:///a.cc", id), {});
+    ASSERT_TRUE(unchanged.Is<RelatedUnchangedDocumentDiagnosticReport>());
+    EXPECT_EQ(unchanged.Get<RelatedUnchangedDocumentDiagnosticReport>()->result_id, id);
+    EXPECT_EQ(cache.LastResultId("file:///a.cc"), id);
+}
+
+TEST(DiagnosticsTest, ResultCacheUnchangedWithStaleResultId) {
+    DiagnosticResultCache cache;
+    auto first = cache.Report(Pull("file:///a.cc"), std::vector{At(0, "a")});
+    String id = *first.Get<RelatedFullDocumentDiagnosticReport>()->result_id;
+
+    // The client does not have the last report, so it is sent again
+    for (auto previous : {Optional<String>{}, Optional<String>{"stale"}}) {
+        auto report = cache.Report(Pull("file:///a.cc", previous), {});
+        ASSERT_TRUE(report.Is<RelatedFullDocumentDiagnosticReport>());
+        auto* full = report.Get<RelatedFullDocumentDiagnosticReport>();
+        EXPECT_EQ(full->result_id, id);
+        EXPECT_THAT(Messages(full->items), testing::ElementsAre("a"));
+    }
+}
+
+TEST(DiagnosticsTest, ResultCacheUnknownDocument) {
+    DiagnosticResultCache cache;
+    auto report = cache.Report(Pull("file:///a.cc", String{"1"}), {});
+    ASSERT_TRUE(report.Is<RelatedFullDocumentDiagnosticReport>());
+    auto* full = report.Get<RelatedFullDocumentDiagnosticReport>();
+    EXPECT_TRUE(full->items.empty());
+    EXPECT_EQ(cache.LastResultId("file:///a.cc"), *full->result_id);
+}
+
+TEST(DiagnosticsTest, ResultCacheForget) {
+    DiagnosticResultCache cache;
+    cache.Report(Pull("file:///a.cc"), std::vector{At(0, "a")});
+    cache.Report(Pull("file:///b.cc"), std::vector{At(0, "b")});
+    cache.Forget("file:///a.cc");
+    EXPECT_FALSE(cache.LastResultId("file:///a.cc"));
+    EXPECT_TRUE(cache.LastResultId("file:///b.cc"));
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
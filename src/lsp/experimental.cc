@@ -0,0 +1,69 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/experimental.h"
+
+namespace langsvr::lsp {
+
+Result<SuccessType> ExperimentalCapabilities::ApplyTo(ServerCapabilities& capabilities) const {
+    return Merge(capabilities.experimental);
+}
+
+Result<SuccessType> ExperimentalCapabilities::ApplyTo(ClientCapabilities& capabilities) const {
+    return Merge(capabilities.experimental);
+}
+
+Result<SuccessType> ExperimentalCapabilities::AddAny(std::string_view key, LSPAny value) {
+    auto [_, added] = object_.emplace(std::string(key), std::move(value));
+    if (!added) {
+        return Failure{"experimental capability '" + std::string(key) + "' already added"};
+    }
+    return Success;
+}
+
+Result<SuccessType> ExperimentalCapabilities::Merge(Optional<LSPAny>& experimental) const {
+    LSPObject merged;
+    if (experimental) {
+        auto* existing = experimental->Get<LSPObject>();
+        if (!existing) {
+            return Failure{"'experimental' capabilities is not an object"};
+        }
+        merged = *existing;
+    }
+    for (auto& [key, value] : object_) {
+        if (merged.count(key)) {
+            return Failure{"experimental capability '" + key + "' is already declared"};
+        }
+        merged.emplace(key, value);
+    }
+    LSPAny any;
+    any.Set(std::move(merged));
+    experimental = std::move(any);
+    return Success;
+}
+
+}  // namespace langsvr::lsp
@@ -0,0 +1,154 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/experimental.h"
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+ClientCapabilities ParseClientCapabilities(std::string_view json) {
+    auto b = json::Builder::Create();
+    auto parsed = b->Parse(json);
+    EXPECT_EQ(parsed, Success);
+    ClientCapabilities out;
+    EXPECT_EQ(Decode(*parsed.Get(), out), Success);
+    return out;
+}
+
+TEST(ExperimentalTest, GetNestedSection) {
+    auto caps = ParseClientCapabilities(R"({
+        "experimental": {
+            "serverStatusNotification": true,
+            "commands": { "commands": ["a", "b"] }
+        }
+    })");
+
+    auto status = GetExperimental<Boolean>(caps, "serverStatusNotification");
+    ASSERT_EQ(status, Success);
+    ASSERT_TRUE(status.Get());
+    EXPECT_TRUE(*status.Get());
+
+    auto commands = GetExperimental<ExecuteCommandOptions>(caps, "commands");
+    ASSERT_EQ(commands, Success);
+    ASSERT_TRUE(commands.Get());
+    EXPECT_THAT(commands.Get()->commands, testing::ElementsAre("a", "b"));
+}
+
+TEST(ExperimentalTest, GetMissingSection) {
+    auto without_experimental = GetExperimental<Boolean>(ClientCapabilities{}, "feature");
+    ASSERT_EQ(without_experimental, Success);
+    EXPECT_FALSE(without_experimental.Get());
+
+    auto caps = ParseClientCapabilities(R"({"experimental": {"other": true}})");
+    auto without_key = GetExperimental<Boolean>(caps, "feature");
+    ASSERT_EQ(without_key, Success);
+    EXPECT_FALSE(without_key.Get());
+}
+
+TEST(ExperimentalTest, GetMalformedSection) {
+    auto caps = ParseClientCapabilities(R"({"experimental": {"commands": {"commands": 42}}})");
+    auto commands = GetExperimental<ExecuteCommandOptions>(caps, "commands");
+    ASSERT_NE(commands, Success);
+    EXPECT_THAT(commands.Failure().reason,
+                testing::HasSubstr("while decoding experimental capability 'commands'"));
+
+    auto not_object = ParseClientCapabilities(R"({"experimental": [1, 2]})");
+    EXPECT_NE(GetExperimental<Boolean>(not_object, "feature"), Success);
+}
+
+TEST(ExperimentalTest, ComposeFeatures) {
+    ExecuteCommandOptions command_options;
+    command_options.commands = {"a"};
+
+    ExperimentalCapabilities experimental;
+    EXPECT_EQ(experimental.Add("serverStatusNotification", Boolean{true}), Success);
+    EXPECT_EQ(experimental.Add("commands", command_options), Success);
+    EXPECT_NE(experimental.Add("commands", Boolean{false}), Success);
+
+    ServerCapabilities caps;
+    ASSERT_EQ(experimental.ApplyTo(caps), Success);
+
+    auto status = GetExperimental<Boolean>(caps, "serverStatusNotification");
+    ASSERT_EQ(status, Success);
+    ASSERT_TRUE(status.Get());
+    EXPECT_TRUE(*status.Get());
+
+    auto commands = GetExperimental<ExecuteCommandOptions>(caps, "commands");
+    ASSERT_EQ(commands, Success);
+    ASSERT_TRUE(commands.Get());
+    EXPECT_THAT(commands.Get()->commands, testing::ElementsAre("a"));
+}
+
+TEST(ExperimentalTest, ApplyPreservesExistingSections) {
+    ExperimentalCapabilities first;
+    EXPECT_EQ(first.Add("first", Boolean{true}), Success);
+    ExperimentalCapabilities second;
+    EXPECT_EQ(second.Add("second", Boolean{true}), Success);
+
+    ServerCapabilities caps;
+    ASSERT_EQ(first.ApplyTo(caps), Success);
+    ASSERT_EQ(second.ApplyTo(caps), Success);
+    ASSERT_TRUE(caps.experimental);
+    EXPECT_EQ(caps.experimental->Get<LSPObject>()->size(), 2u);
+
+    EXPECT_NE(first.ApplyTo(caps), Success);
+}
+
+TEST(ExperimentalTest, RoundTrip) {
+    auto b = json::Builder::Create();
+    auto parsed = b->Parse(R"({
+        "capabilities": {
+            "experimental": {
+                "hoverActions": true,
+                "runnables": { "kinds": ["cargo", "shell"], "limits": { "max": 3, "ratio": 0.5 } },
+                "empty": {},
+                "nothing": null
+            }
+        }
+    })");
+    ASSERT_EQ(parsed, Success);
+
+    InitializeResult result;
+    ASSERT_EQ(Decode(*parsed.Get(), result), Success);
+    ASSERT_TRUE(result.capabilities.experimental);
+
+    auto encoded = Encode(result, *b);
+    ASSERT_EQ(encoded, Success);
+    EXPECT_EQ(encoded.Get()->Json(), parsed.Get()->Json());
+
+    InitializeResult decoded;
+    ASSERT_EQ(Decode(*encoded.Get(), decoded), Success);
+    auto runnables = GetExperimental<LSPObject>(decoded.capabilities, "runnables");
+    ASSERT_EQ(runnables, Success);
+    ASSERT_TRUE(runnables.Get());
+    EXPECT_EQ(runnables.Get()->size(), 2u);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
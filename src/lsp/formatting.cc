@@ -0,0 +1,316 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/formatting.h"
+
+#include <algorithm>
+#include <cctype>
+#include <charconv>
+#include <string>
+#include <utility>
+#include <vector>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// @returns @p str with the leading and trailing whitespace removed
+std::string_view Trim(std::string_view str) {
+    while (!str.empty() && std::isspace(static_cast<unsigned char>(str.front()))) {
+        str.remove_prefix(1);
+    }
+    while (!str.empty() && std::isspace(static_cast<unsigned char>(str.back()))) {
+        str.remove_suffix(1);
+    }
+    return str;
+}
+
+/// @returns @p str converted to lower case
+std::string Lower(std::string_view str) {
+    std::string out(str);
+    std::transform(out.begin(), out.end(), out.begin(),
+                   [](unsigned char c) { return static_cast<char>(std::tolower(c)); });
+    return out;
+}
+
+/// ExpandBraces appends to @p out each of the patterns produced by expanding the '{a,b}'
+/// alternatives of @p pattern, starting the search for braces at @p from. Braces without a
+/// top-level comma, and unmatched braces, are left in place and matched literally.
+void ExpandBraces(const std::string& pattern, size_t from, std::vector<std::string>& out) {
+    for (size_t i = from; i < pattern.size(); i++) {
+        if (pattern[i] == '\\') {
+            i++;
+            continue;
+        }
+        if (pattern[i] != '{') {
+            continue;
+        }
+        std::vector<size_t> commas;
+        size_t close = std::string::npos;
+        int depth = 0;
+        for (size_t j = i + 1; j < pattern.size() && close == std::string::npos; j++) {
+            switch (pattern[j]) {
+                case '\\':
+                    j++;
+                    break;
+                case '{':
+                    depth++;
+                    break;
+                case '}':
+                    if (depth == 0) {
+                        close = j;
+                    }
+                    depth--;
+                    break;
+                case ',':
+                    if (depth == 0) {
+                        commas.push_back(j);
+                    }
+                    break;
+            }
+        }
+        if (close == std::string::npos) {
+            break;
+        }
+        if (commas.empty()) {
+            continue;
+        }
+        commas.push_back(close);
+        size_t start = i + 1;
+        for (size_t comma : commas) {
+            ExpandBraces(pattern.substr(0, i) + pattern.substr(start, comma - start) +
+                             pattern.substr(close + 1),
+                         i, out);
+            start = comma + 1;
+        }
+        return;
+    }
+    out.push_back(pattern);
+}
+
+/// @returns true if the brace-expanded glob @p pattern matches the whole of @p path
+bool Match(std::string_view pattern, std::string_view path) {
+    while (!pattern.empty()) {
+        switch (pattern[0]) {
+            case '*': {
+                bool any_dir = pattern.size() > 1 && pattern[1] == '*';
+                auto rest = pattern.substr(any_dir ? 2 : 1);
+                // '**/' also matches zero directories
+                if (any_dir && !rest.empty() && rest[0] == '/' && Match(rest.substr(1), path)) {
+                    return true;
+                }
+                for (size_t i = 0;; i++) {
+                    if (Match(rest, path.substr(i))) {
+                        return true;
+                    }
+                    if (i == path.size() || (!any_dir && path[i] == '/')) {
+                        return false;
+                    }
+                }
+            }
+            case '?':
+                if (path.empty() || path[0] == '/') {
+                    return false;
+                }
+                break;
+            case '[': {
+                bool negate = pattern.size() > 1 && pattern[1] == '!';
+                size_t close = pattern.find(']', negate ? 3 : 2);
+                auto set = pattern.substr(negate ? 2 : 1, close - (negate ? 2 : 1));
+                if (close == std::string_view::npos || set.find('/') != std::string_view::npos) {
+                    // Not a bracket expression. Match the '[' literally.
+                    if (path.empty() || path[0] != '[') {
+                        return false;
+                    }
+                    break;
+                }
+                if (path.empty() || path[0] == '/') {
+                    return false;
+                }
+                bool in_set = false;
+                for (size_t i = 0; i < set.size(); i++) {
+                    if (i + 2 < set.size() && set[i + 1] == '-') {
+                        in_set |= path[0] >= set[i] && path[0] <= set[i + 2];
+                        i += 2;
+                    } else {
+                        in_set |= path[0] == set[i];
+                    }
+                }
+                if (in_set == negate) {
+                    return false;
+                }
+                pattern.remove_prefix(close);
+                break;
+            }
+            case '\\':
+                if (pattern.size() > 1) {
+                    pattern.remove_prefix(1);
+                }
+                [[fallthrough]];
+            default:
+                if (path.empty() || path[0] != pattern[0]) {
+                    return false;
+                }
+                break;
+        }
+        pattern.remove_prefix(1);
+        path.remove_prefix(1);
+    }
+    return path.empty();
+}
+
+/// @returns true if the '.editorconfig' section glob @p glob matches @p path
+bool SectionMatches(std::string_view glob, std::string_view path) {
+    std::string pattern(glob);
+    if (pattern.find('/') == std::string::npos) {
+        // A glob without a '/' matches the file name in any directory
+        pattern = "**/" + pattern;
+    } else if (pattern[0] == '/') {
+        pattern = pattern.substr(1);
+    }
+    std::vector<std::string> patterns;
+    ExpandBraces(pattern, 0, patterns);
+    return std::any_of(patterns.begin(), patterns.end(),
+                       [&](const std::string& p) { return Match(p, path); });
+}
+
+/// @returns the positive integer held by @p value, or an unset Optional if @p value is not a
+/// positive integer
+Optional<Uinteger> ParseSize(std::string_view value) {
+    Uinteger size = 0;
+    auto [end, err] = std::from_chars(value.data(), value.data() + value.size(), size);
+    if (err != std::errc{} || end != value.data() + value.size() || size == 0) {
+        return {};
+    }
+    return size;
+}
+
+/// Sets @p field from the boolean '.editorconfig' property value @p value
+void SetBool(Optional<Boolean>& field, std::string_view value) {
+    if (value == "true") {
+        field = true;
+    } else if (value == "false") {
+        field = false;
+    } else if (value == "unset") {
+        field.Reset();
+    }
+}
+
+}  // namespace
+
+FormattingOptions MergeFormattingOptions(FormattingOptions client,
+                                         const FormattingOverrides& server) {
+    if (server.tab_size) {
+        client.tab_size = *server.tab_size;
+    }
+    if (server.insert_spaces) {
+        client.insert_spaces = *server.insert_spaces;
+    }
+    if (server.trim_trailing_whitespace) {
+        client.trim_trailing_whitespace = *server.trim_trailing_whitespace;
+    }
+    if (server.insert_final_newline) {
+        client.insert_final_newline = *server.insert_final_newline;
+    }
+    if (server.trim_final_newlines) {
+        client.trim_final_newlines = *server.trim_final_newlines;
+    }
+    return client;
+}
+
+Result<FormattingOverrides> FormattingOverridesFromEditorConfig(std::string_view editorconfig,
+                                                                std::string_view path) {
+    FormattingOverrides out;
+    Optional<Uinteger> indent_size;
+    Optional<Uinteger> tab_width;
+    bool in_matching_section = false;
+
+    size_t line_number = 0;
+    while (!editorconfig.empty()) {
+        line_number++;
+        size_t eol = editorconfig.find('\n');
+        auto line = Trim(editorconfig.substr(0, eol));
+        editorconfig.remove_prefix(eol == std::string_view::npos ? editorconfig.size() : eol + 1);
+
+        if (line.empty() || line[0] == '#' || line[0] == ';') {
+            continue;
+        }
+        if (line[0] == '[') {
+            if (line.back() != ']') {
+                return Failure{".editorconfig:" + std::to_string(line_number) +
+                               ": unterminated section header"};
+            }
+            in_matching_section = SectionMatches(line.substr(1, line.size() - 2), path);
+            continue;
+        }
+        size_t eq = line.find('=');
+        if (eq == std::string_view::npos) {
+            return Failure{".editorconfig:" + std::to_string(line_number) +
+                           ": expected a section header or 'key = value'"};
+        }
+        if (!in_matching_section) {
+            continue;  // Preamble, such as 'root = true', or a section for other files
+        }
+
+        auto key = Lower(Trim(line.substr(0, eq)));
+        auto value = Lower(Trim(line.substr(eq + 1)));
+        if (key == "indent_style") {
+            if (value == "space") {
+                out.insert_spaces = true;
+            } else if (value == "tab") {
+                out.insert_spaces = false;
+            } else if (value == "unset") {
+                out.insert_spaces.Reset();
+            }
+        } else if (key == "indent_size") {
+            if (value == "tab" || value == "unset") {
+                indent_size.Reset();
+            } else if (auto size = ParseSize(value)) {
+                indent_size = size;
+            }
+        } else if (key == "tab_width") {
+            if (value == "unset") {
+                tab_width.Reset();
+            } else if (auto size = ParseSize(value)) {
+                tab_width = size;
+            }
+        } else if (key == "trim_trailing_whitespace") {
+            SetBool(out.trim_trailing_whitespace, value);
+        } else if (key == "insert_final_newline") {
+            SetBool(out.insert_final_newline, value);
+        }
+    }
+
+    if (indent_size) {
+        out.tab_size = indent_size;
+    } else if (tab_width) {
+        out.tab_size = tab_width;
+    }
+    return out;
+}
+
+}  // namespace langsvr::lsp
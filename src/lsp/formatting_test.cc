@@ -0,0 +1,171 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/formatting.h"
+
+#include <string>
+#include <string_view>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+FormattingOptions Client() {
+    FormattingOptions options;
+    options.tab_size = 2;
+    options.insert_spaces = true;
+    options.trim_trailing_whitespace = true;
+    return options;
+}
+
+TEST(FormattingTest, MergeNoOverrides) {
+    auto got = MergeFormattingOptions(Client(), {});
+    EXPECT_EQ(got.tab_size, 2u);
+    EXPECT_TRUE(got.insert_spaces);
+    EXPECT_EQ(got.trim_trailing_whitespace, true);
+    EXPECT_FALSE(got.insert_final_newline);
+}
+
+TEST(FormattingTest, MergeOverrides) {
+    FormattingOverrides server;
+    server.tab_size = 4;
+    server.insert_spaces = false;
+    server.insert_final_newline = true;
+
+    auto got = MergeFormattingOptions(Client(), server);
+    EXPECT_EQ(got.tab_size, 4u);
+    EXPECT_FALSE(got.insert_spaces);
+    EXPECT_EQ(got.trim_trailing_whitespace, true);
+    EXPECT_EQ(got.insert_final_newline, true);
+    EXPECT_FALSE(got.trim_final_newlines);
+}
+
+constexpr std::string_view kEditorConfig = R"(
+# top-most EditorConfig file
+root = true
+
+[*]
+insert_final_newline = true
+trim_trailing_whitespace = true
+
+[*.{cc,h}]
+indent_style = space
+indent_size = 4
+
+[Makefile]
+indent_style = tab
+tab_width = 8
+
+; Section globs containing a '/' are relative to the .editorconfig
+[third_party/**]
+trim_trailing_whitespace = unset
+insert_final_newline = false
+
+[docs/*.md]
+indent_size = tab
+tab_width = 3
+)";
+
+TEST(FormattingTest, EditorConfig) {
+    auto cc = FormattingOverridesFromEditorConfig(kEditorConfig, "src/lsp/formatting.cc");
+    ASSERT_EQ(cc, Success);
+    EXPECT_EQ(cc->tab_size, 4u);
+    EXPECT_EQ(cc->insert_spaces, true);
+    EXPECT_EQ(cc->trim_trailing_whitespace, true);
+    EXPECT_EQ(cc->insert_final_newline, true);
+    EXPECT_FALSE(cc->trim_final_newlines);
+
+    auto makefile = FormattingOverridesFromEditorConfig(kEditorConfig, "Makefile");
+    ASSERT_EQ(makefile, Success);
+    EXPECT_EQ(makefile->tab_size, 8u);
+    EXPECT_EQ(makefile->insert_spaces, false);
+
+    auto third_party = FormattingOverridesFromEditorConfig(kEditorConfig, "third_party/x/y.h");
+    ASSERT_EQ(third_party, Success);
+    EXPECT_EQ(third_party->tab_size, 4u);
+    EXPECT_FALSE(third_party->trim_trailing_whitespace);
+    EXPECT_EQ(third_party->insert_final_newline, false);
+
+    auto md = FormattingOverridesFromEditorConfig(kEditorConfig, "docs/README.md");
+    ASSERT_EQ(md, Success);
+    EXPECT_EQ(md->tab_size, 3u);
+    EXPECT_FALSE(md->insert_spaces);
+
+    auto nested_md = FormattingOverridesFromEditorConfig(kEditorConfig, "docs/a/README.md");
+    ASSERT_EQ(nested_md, Success);
+    EXPECT_FALSE(nested_md->tab_size);
+}
+
+TEST(FormattingTest, EditorConfigGlobs) {
+    auto matches = [](std::string glob, std::string path) {
+        auto config = "[" + glob + "]\nindent_style = tab\n";
+        auto got = FormattingOverridesFromEditorConfig(config, path);
+        EXPECT_EQ(got, Success);
+        return got->insert_spaces == false;
+    };
+    EXPECT_TRUE(matches("*.go", "main.go"));
+    EXPECT_TRUE(matches("*.go", "a/b/main.go"));
+    EXPECT_FALSE(matches("*.go", "main.goo"));
+    EXPECT_TRUE(matches("/*.go", "main.go"));
+    EXPECT_FALSE(matches("/*.go", "a/main.go"));
+    EXPECT_TRUE(matches("a/**/z.txt", "a/z.txt"));
+    EXPECT_TRUE(matches("a/**/z.txt", "a/b/c/z.txt"));
+    EXPECT_FALSE(matches("a/*/z.txt", "a/b/c/z.txt"));
+    EXPECT_TRUE(matches("file?.txt", "file1.txt"));
+    EXPECT_FALSE(matches("file?.txt", "file.txt"));
+    EXPECT_TRUE(matches("[abc].txt", "b.txt"));
+    EXPECT_FALSE(matches("[!abc].txt", "b.txt"));
+    EXPECT_TRUE(matches("[a-c]x", "cx"));
+    EXPECT_TRUE(matches("{lib,src}/**.{c,h}", "src/x/y.h"));
+    EXPECT_FALSE(matches("{lib,src}/**.{c,h}", "test/y.h"));
+    EXPECT_TRUE(matches("{single}.txt", "{single}.txt"));
+    EXPECT_TRUE(matches("\\*.txt", "*.txt"));
+    EXPECT_FALSE(matches("\\*.txt", "a.txt"));
+}
+
+TEST(FormattingTest, EditorConfigCaseAndInvalidValues) {
+    auto got = FormattingOverridesFromEditorConfig(
+        "[*]\nINDENT_STYLE = Space\nindent_size = many\ntab_width = 0\n", "x");
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got->insert_spaces, true);
+    EXPECT_FALSE(got->tab_size);
+}
+
+TEST(FormattingTest, EditorConfigMalformed) {
+    auto header = FormattingOverridesFromEditorConfig("[*]\nindent_size = 2\n[*.cc\n", "x");
+    ASSERT_NE(header, Success);
+    EXPECT_EQ(header.Failure().reason, ".editorconfig:3: unterminated section header");
+
+    auto property = FormattingOverridesFromEditorConfig("[*]\nindent_size 2\n", "x");
+    ASSERT_NE(property, Success);
+    EXPECT_EQ(property.Failure().reason,
+              ".editorconfig:2: expected a section header or 'key = value'");
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,87 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/lsp/locations.h"
+
+#include <algorithm>
+#include <cstddef>
+#include <set>
+#include <string_view>
+#include <tuple>
+#include <utility>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// LocationKey holds the fields that identify and order locations
+using LocationKey = std::tuple<std::string_view,  // uri
+                               Uinteger,          // range.start.line
+                               Uinteger,          // range.start.character
+                               Uinteger,          // range.end.line
+                               Uinteger           // range.end.character
+                               >;
+
+/// @returns the LocationKey of @p location
+LocationKey KeyOf(const Location& location) {
+    auto& range = location.range;
+    return {location.uri, range.start.line, range.start.character, range.end.line,
+            range.end.character};
+}
+
+}  // namespace
+
+std::vector<Location> DeduplicateLocations(std::vector<Location> locations) {
+    // The keys reference the URIs held by @p locations, so find the duplicates before moving any
+    // of the locations.
+    std::set<LocationKey> seen;
+    std::vector<bool> keep(locations.size());
+    for (size_t i = 0; i < locations.size(); i++) {
+        keep[i] = seen.emplace(KeyOf(locations[i])).second;
+    }
+    std::vector<Location> out;
+    out.reserve(seen.size());
+    for (size_t i = 0; i < locations.size(); i++) {
+        if (keep[i]) {
+            out.push_back(std::move(locations[i]));
+        }
+    }
+    return out;
+}
+
+std::vector<Location> SortLocations(std::vector<Location> locations) {
+    std::stable_sort(locations.begin(), locations.end(),
+                     [](const Location& a, const Location& b) { return KeyOf(a) < KeyOf(b); });
+    return locations;
+}
+
+std::vector<Location> NormalizeLocations(std::vector<Location> locations) {
+    return DeduplicateLocations(SortLocations(std::move(locations)));
+}
+
+}  // namespace langsvr::lsp
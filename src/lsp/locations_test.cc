@@ -0,0 +1,107 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+
+#include "langsvr/lsp/locations.h"
+
+#include <string>
+#include <vector>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+Location Loc(std::string uri, Uinteger line, Uinteger character) {
+    Location location;
+    location.uri = std::move(uri);
+    location.range.start = Position{line, character};
+    location.range.end = Position{line, character + 3};
+    return location;
+}
+
+/// @returns the locations as 'uri:line:character' strings
+std::vector<std::string> Strings(const std::vector<Location>& locations) {
+    std::vector<std::string> out;
+    for (auto& location : locations) {
+        out.push_back(location.uri + ":" + std::to_string(location.range.start.line) + ":" +
+                      std::to_string(location.range.start.character));
+    }
+    return out;
+}
+
+TEST(LocationsTest, Deduplicate) {
+    std::vector<Location> locations{
+        Loc("file:///b.cc", 4, 2),
+        Loc("file:///a.cc", 1, 0),
+        Loc("file:///b.cc", 4, 2),
+        Loc("file:///b.cc", 4, 3),
+        Loc("file:///a.cc", 1, 0),
+    };
+    locations[3].range.end.character = 10;
+
+    auto got = DeduplicateLocations(locations);
+    EXPECT_THAT(Strings(got), testing::ElementsAre("file:///b.cc:4:2", "file:///a.cc:1:0",
+                                                   "file:///b.cc:4:3"));
+    EXPECT_EQ(locations.size(), 5u);
+}
+
+TEST(LocationsTest, DeduplicateByRangeEnd) {
+    std::vector<Location> locations{Loc("file:///a.cc", 1, 0), Loc("file:///a.cc", 1, 0)};
+    locations[1].range.end.character = 10;
+    EXPECT_EQ(DeduplicateLocations(locations).size(), 2u);
+}
+
+TEST(LocationsTest, Sort) {
+    std::vector<Location> locations{
+        Loc("file:///b.cc", 4, 2),
+        Loc("file:///a.cc", 10, 0),
+        Loc("file:///b.cc", 1, 7),
+        Loc("file:///a.cc", 2, 5),
+        Loc("file:///b.cc", 1, 3),
+    };
+    auto got = SortLocations(locations);
+    EXPECT_THAT(Strings(got),
+                testing::ElementsAre("file:///a.cc:2:5", "file:///a.cc:10:0", "file:///b.cc:1:3",
+                                     "file:///b.cc:1:7", "file:///b.cc:4:2"));
+    EXPECT_EQ(locations[0].uri, "file:///b.cc");
+}
+
+TEST(LocationsTest, Normalize) {
+    std::vector<Location> locations{
+        Loc("file:///b.cc", 4, 2),
+        Loc("file:///a.cc", 1, 0),
+        Loc("file:///b.cc", 4, 2),
+        Loc("file:///a.cc", 1, 0),
+    };
+    EXPECT_THAT(Strings(NormalizeLocations(locations)),
+                testing::ElementsAre("file:///a.cc:1:0", "file:///b.cc:4:2"));
+    EXPECT_TRUE(NormalizeLocations({}).empty());
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -57,6 +57,29 @@ Result<SuccessType> MatchKind(V& v, std::string_view want) {
     return Success;
 }
 
+/// EncodeBase encodes the base structure @p in, appending the encoded members to @p members
+template <typename T>
+Result<SuccessType> EncodeBase(const T& in,
+                               json::Builder& b,
+                               std::vector<json::Builder::Member>& members) {
+    auto object = Encode(in, b);
+    if (object != Success) {
+        return object.Failure();
+    }
+    auto names = object.Get()->MemberNames();
+    if (names != Success) {
+        return names.Failure();
+    }
+    for (auto& name : names.Get()) {
+        auto member = object.Get()->Get(name);
+        if (member != Success) {
+            return member.Failure();
+        }
+        members.push_back(json::Builder::Member{name, member.Get()});
+    }
+    return Success;
+}
+
 }  // namespace
 
 Result<SuccessType> Decode(V& v, SemanticTokenTypes& out) {
@@ -2008,10 +2031,78 @@ Result<const json::Value*> Encode([[maybe_unused]] const TextDocumentPositionPar
     return b.Object(members);
 }
 
+Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkDoneProgressParams& out) {
+    if (v.Has("workDoneToken")) {
+        lsp::ProgressToken val;
+        auto member = v.Get("workDoneToken");
+        if (member != Success) {
+            return member.Failure();
+        }
+        if (auto res = Decode(*member.Get(), val); res != Success) {
+            return res.Failure();
+        }
+        out.work_done_token = std::move(val);
+    }
+
+    return Success;
+}
+
+Result<const json::Value*> Encode([[maybe_unused]] const WorkDoneProgressParams& in,
+                                  [[maybe_unused]] json::Builder& b) {
+    std::vector<json::Builder::Member> members;
+    members.reserve(1);
+    if (in.work_done_token) {
+        auto res = Encode(*in.work_done_token, b);
+        if (res != Success) {
+            return res.Failure();
+        }
+        members.push_back(json::Builder::Member{"workDoneToken", res.Get()});
+    }
+
+    return b.Object(members);
+}
+
+Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] PartialResultParams& out) {
+    if (v.Has("partialResultToken")) {
+        lsp::ProgressToken val;
+        auto member = v.Get("partialResultToken");
+        if (member != Success) {
+            return member.Failure();
+        }
+        if (auto res = Decode(*member.Get(), val); res != Success) {
+            return res.Failure();
+        }
+        out.partial_result_token = std::move(val);
+    }
+
+    return Success;
+}
+
+Result<const json::Value*> Encode([[maybe_unused]] const PartialResultParams& in,
+                                  [[maybe_unused]] json::Builder& b) {
+    std::vector<json::Builder::Member> members;
+    members.reserve(1);
+    if (in.partial_result_token) {
+        auto res = Encode(*in.partial_result_token, b);
+        if (res != Success) {
+            return res.Failure();
+        }
+        members.push_back(json::Builder::Member{"partialResultToken", res.Get()});
+    }
+
+    return b.Object(members);
+}
+
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ImplementationParams& out) {
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2020,7 +2111,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const ImplementationParams& i
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -2147,7 +2247,42 @@ Result<const json::Value*> Encode([[maybe_unused]] const TextDocumentRegistratio
     return b.Object(members);
 }
 
+Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkDoneProgressOptions& out) {
+    if (v.Has("workDoneProgress")) {
+        Boolean val;
+        auto member = v.Get("workDoneProgress");
+        if (member != Success) {
+            return member.Failure();
+        }
+        if (auto res = Decode(*member.Get(), val); res != Success) {
+            return res.Failure();
+        }
+        out.work_done_progress = std::move(val);
+    }
+
+    return Success;
+}
+
+Result<const json::Value*> Encode([[maybe_unused]] const WorkDoneProgressOptions& in,
+                                  [[maybe_unused]] json::Builder& b) {
+    std::vector<json::Builder::Member> members;
+    members.reserve(1);
+    if (in.work_done_progress) {
+        auto res = Encode(*in.work_done_progress, b);
+        if (res != Success) {
+            return res.Failure();
+        }
+        members.push_back(json::Builder::Member{"workDoneProgress", res.Get()});
+    }
+
+    return b.Object(members);
+}
+
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ImplementationOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -2155,6 +2290,41 @@ Result<const json::Value*> Encode([[maybe_unused]] const ImplementationOptions&
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+
+    return b.Object(members);
+}
+
+Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] StaticRegistrationOptions& out) {
+    if (v.Has("id")) {
+        String val;
+        auto member = v.Get("id");
+        if (member != Success) {
+            return member.Failure();
+        }
+        if (auto res = Decode(*member.Get(), val); res != Success) {
+            return res.Failure();
+        }
+        out.id = std::move(val);
+    }
+
+    return Success;
+}
+
+Result<const json::Value*> Encode([[maybe_unused]] const StaticRegistrationOptions& in,
+                                  [[maybe_unused]] json::Builder& b) {
+    std::vector<json::Builder::Member> members;
+    members.reserve(1);
+    if (in.id) {
+        auto res = Encode(*in.id, b);
+        if (res != Success) {
+            return res.Failure();
+        }
+        members.push_back(json::Builder::Member{"id", res.Get()});
+    }
 
     return b.Object(members);
 }
@@ -2167,6 +2337,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<ImplementationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2175,11 +2348,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const ImplementationRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const ImplementationOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ImplementationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -2190,6 +2368,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] TypeDefinitio
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2198,7 +2382,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeDefinitionParams& i
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -2206,6 +2399,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeDefinitionParams& i
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] TypeDefinitionOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -2213,6 +2410,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeDefinitionOptions&
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -2225,6 +2426,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TypeDefinitionOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2233,11 +2437,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeDefinitionRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TypeDefinitionOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TypeDefinitionOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -2453,6 +2662,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentColor
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2468,6 +2683,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentColorParams& in
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -2595,6 +2818,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const ColorInformation& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentColorOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -2602,6 +2829,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentColorOptions& i
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -2614,6 +2845,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<DocumentColorOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2622,11 +2856,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentColorRegistrati
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const DocumentColorOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentColorOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -2661,6 +2900,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ColorPresenta
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2690,6 +2935,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const ColorPresentationParams
         }
         members.push_back(json::Builder::Member{"range", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -2804,37 +3057,6 @@ Result<const json::Value*> Encode([[maybe_unused]] const ColorPresentation& in,
     return b.Object(members);
 }
 
-Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkDoneProgressOptions& out) {
-    if (v.Has("workDoneProgress")) {
-        Boolean val;
-        auto member = v.Get("workDoneProgress");
-        if (member != Success) {
-            return member.Failure();
-        }
-        if (auto res = Decode(*member.Get(), val); res != Success) {
-            return res.Failure();
-        }
-        out.work_done_progress = std::move(val);
-    }
-
-    return Success;
-}
-
-Result<const json::Value*> Encode([[maybe_unused]] const WorkDoneProgressOptions& in,
-                                  [[maybe_unused]] json::Builder& b) {
-    std::vector<json::Builder::Member> members;
-    members.reserve(1);
-    if (in.work_done_progress) {
-        auto res = Encode(*in.work_done_progress, b);
-        if (res != Success) {
-            return res.Failure();
-        }
-        members.push_back(json::Builder::Member{"workDoneProgress", res.Get()});
-    }
-
-    return b.Object(members);
-}
-
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] FoldingRangeParams& out) {
     {
         auto member = v.Get("textDocument");
@@ -2845,6 +3067,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] FoldingRangeP
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -2860,6 +3088,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const FoldingRangeParams& in,
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -2982,6 +3218,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const FoldingRange& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] FoldingRangeOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -2989,6 +3229,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const FoldingRangeOptions& in
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3001,6 +3245,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<FoldingRangeOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3009,11 +3256,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const FoldingRangeRegistratio
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const FoldingRangeOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const FoldingRangeOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -3024,6 +3276,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DeclarationPa
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3032,7 +3290,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const DeclarationParams& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -3040,6 +3307,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DeclarationParams& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DeclarationOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -3047,6 +3318,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DeclarationOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3059,6 +3334,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentRegistrationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3067,10 +3345,15 @@ Result<const json::Value*> Encode([[maybe_unused]] const DeclarationRegistration
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const DeclarationOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DeclarationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -3097,6 +3380,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SelectionRang
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3119,6 +3408,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const SelectionRangeParams& i
         }
         members.push_back(json::Builder::Member{"positions", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3171,6 +3468,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const SelectionRange& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SelectionRangeOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -3178,6 +3479,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const SelectionRangeOptions&
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3190,6 +3495,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentRegistrationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3198,10 +3506,15 @@ Result<const json::Value*> Encode([[maybe_unused]] const SelectionRangeRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const SelectionRangeOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const SelectionRangeOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -3274,6 +3587,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3282,7 +3598,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyPreparePar
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -3437,6 +3758,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyItem& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CallHierarchyOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -3444,6 +3769,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyOptions& i
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3456,6 +3785,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<CallHierarchyOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3464,11 +3796,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyRegistrati
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const CallHierarchyOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const CallHierarchyOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -3486,6 +3823,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3501,6 +3844,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyIncomingCa
         }
         members.push_back(json::Builder::Member{"item", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3561,6 +3912,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3576,6 +3933,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const CallHierarchyOutgoingCa
         }
         members.push_back(json::Builder::Member{"item", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3635,6 +4000,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SemanticToken
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3650,6 +4021,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const SemanticTokensParams& i
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3839,6 +4218,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SemanticToken
         }
         out.full = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3868,6 +4250,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const SemanticTokensOptions&
         }
         members.push_back(json::Builder::Member{"full", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -3892,6 +4278,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<SemanticTokensOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3900,11 +4289,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const SemanticTokensRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const SemanticTokensOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const SemanticTokensOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -3930,6 +4324,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SemanticToken
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -3952,6 +4352,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const SemanticTokensDeltaPara
         }
         members.push_back(json::Builder::Member{"previousResultId", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -4115,6 +4523,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SemanticToken
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -4137,6 +4551,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const SemanticTokensRangePara
         }
         members.push_back(json::Builder::Member{"range", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -4257,6 +4679,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] LinkedEditing
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -4265,7 +4690,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const LinkedEditingRangeParam
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4320,6 +4750,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const LinkedEditingRanges& in
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] LinkedEditingRangeOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -4327,6 +4761,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const LinkedEditingRangeOptio
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -4339,6 +4777,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<LinkedEditingRangeOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -4347,11 +4788,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const LinkedEditingRangeRegis
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const LinkedEditingRangeOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const LinkedEditingRangeOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4562,7 +5008,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const DeleteFile& in,
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
-    if (auto res = Encode(static_cast<const ResourceOperation&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ResourceOperation&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4684,7 +5131,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const RenameFile& in,
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
-    if (auto res = Encode(static_cast<const ResourceOperation&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ResourceOperation&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4790,7 +5238,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const CreateFile& in,
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
-    if (auto res = Encode(static_cast<const ResourceOperation&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ResourceOperation&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4827,7 +5276,8 @@ Result<const json::Value*> Encode(
         }
         members.push_back(json::Builder::Member{"version", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentIdentifier&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentIdentifier&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -4862,7 +5312,7 @@ Result<const json::Value*> Encode([[maybe_unused]] const AnnotatedTextEdit& in,
         }
         members.push_back(json::Builder::Member{"annotationId", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextEdit&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextEdit&>(in), b, members); res != Success) {
         return res.Failure();
     }
 
@@ -5357,6 +5807,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] MonikerParams
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5365,7 +5821,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const MonikerParams& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -5452,6 +5917,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const Moniker& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] MonikerOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -5459,6 +5928,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const MonikerOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -5479,11 +5952,11 @@ Result<const json::Value*> Encode([[maybe_unused]] const MonikerRegistrationOpti
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const MonikerOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const MonikerOptions&>(in), b, members); res != Success) {
         return res.Failure();
     }
 
@@ -5495,6 +5968,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5503,7 +5979,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchyPreparePar
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -5658,6 +6139,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchyItem& in,
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] TypeHierarchyOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -5665,6 +6150,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchyOptions& i
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -5677,6 +6166,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TypeHierarchyOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5685,11 +6177,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchyRegistrati
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TypeHierarchyOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TypeHierarchyOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -5707,6 +6204,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5722,6 +6225,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchySupertypes
         }
         members.push_back(json::Builder::Member{"item", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -5737,6 +6248,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5752,6 +6269,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const TypeHierarchySubtypesPa
         }
         members.push_back(json::Builder::Member{"item", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -5829,6 +6354,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlineValuePa
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5858,11 +6386,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineValueParams& in,
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlineValueOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -5870,6 +6406,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineValueOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -5882,6 +6422,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentRegistrationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5890,10 +6433,15 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineValueRegistration
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const InlineValueOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const InlineValueOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -5920,6 +6468,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlayHintPara
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -5942,6 +6493,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlayHintParams& in,
         }
         members.push_back(json::Builder::Member{"range", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -6302,6 +6857,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlayHintOpti
         }
         out.resolve_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6317,6 +6875,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlayHintOptions& in,
         }
         members.push_back(json::Builder::Member{"resolveProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -6329,6 +6891,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentRegistrationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6337,10 +6902,15 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlayHintRegistrationOp
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const InlayHintOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const InlayHintOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -6380,6 +6950,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentDiagn
         }
         out.previous_result_id = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6409,6 +6985,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentDiagnosticParam
         }
         members.push_back(json::Builder::Member{"previousResultId", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -6835,6 +7419,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DiagnosticOpt
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6864,6 +7451,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DiagnosticOptions& in,
         }
         members.push_back(json::Builder::Member{"workspaceDiagnostics", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -6876,6 +7467,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<DiagnosticOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6884,11 +7478,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const DiagnosticRegistrationO
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const DiagnosticOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DiagnosticOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -6961,6 +7560,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkspaceDiag
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -6983,6 +7588,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceDiagnosticPara
         }
         members.push_back(json::Builder::Member{"previousResultIds", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -7596,7 +8209,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const VersionedTextDocumentId
         }
         members.push_back(json::Builder::Member{"version", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentIdentifier&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentIdentifier&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -8024,6 +8638,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlineComplet
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -8039,7 +8656,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineCompletionParams&
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -8192,6 +8814,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineCompletionList& i
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlineCompletionOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -8199,6 +8825,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineCompletionOptions
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -8211,6 +8841,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<TextDocumentRegistrationOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -8219,10 +8852,15 @@ Result<const json::Value*> Encode([[maybe_unused]] const InlineCompletionRegistr
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const InlineCompletionOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const InlineCompletionOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -13355,6 +13993,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InitializePar
         }
         out.trace = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -13419,6 +14060,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const InitializeParamsBase& i
         }
         members.push_back(json::Builder::Member{"trace", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -13470,10 +14115,11 @@ Result<const json::Value*> Encode([[maybe_unused]] const InitializeParams& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const InitializeParamsBase&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const InitializeParamsBase&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const WorkspaceFoldersInitializeParams&>(in), b);
+    if (auto res = EncodeBase(static_cast<const WorkspaceFoldersInitializeParams&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -13793,6 +14439,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
     if (auto res = Decode(v, static_cast<NotebookDocumentSyncOptions&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<StaticRegistrationOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -13802,7 +14451,12 @@ Result<const json::Value*> Encode(
     [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const NotebookDocumentSyncOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const NotebookDocumentSyncOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const StaticRegistrationOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -13886,6 +14540,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CompletionOpt
         }
         out.completion_item = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -13922,11 +14579,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const CompletionOptions& in,
         }
         members.push_back(json::Builder::Member{"completionItem", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] HoverOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -13934,6 +14599,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const HoverOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -13961,6 +14630,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SignatureHelp
         }
         out.retrigger_characters = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -13983,11 +14655,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const SignatureHelpOptions& i
         }
         members.push_back(json::Builder::Member{"retriggerCharacters", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DefinitionOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -13995,11 +14675,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const DefinitionOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ReferenceOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -14007,11 +14695,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const ReferenceOptions& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentHighlightOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -14019,6 +14715,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentHighlightOption
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14035,6 +14735,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentSymbo
         }
         out.label = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14050,6 +14753,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentSymbolOptions&
         }
         members.push_back(json::Builder::Member{"label", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14077,6 +14784,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CodeActionOpt
         }
         out.resolve_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14099,6 +14809,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeActionOptions& in,
         }
         members.push_back(json::Builder::Member{"resolveProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14115,6 +14829,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CodeLensOptio
         }
         out.resolve_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14130,6 +14847,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeLensOptions& in,
         }
         members.push_back(json::Builder::Member{"resolveProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14146,6 +14867,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentLinkO
         }
         out.resolve_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14161,6 +14885,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentLinkOptions& in
         }
         members.push_back(json::Builder::Member{"resolveProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14177,6 +14905,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkspaceSymb
         }
         out.resolve_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14192,11 +14923,19 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceSymbolOptions&
         }
         members.push_back(json::Builder::Member{"resolveProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
 
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentFormattingOptions& out) {
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
+
     return Success;
 }
 
@@ -14204,6 +14943,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentFormattingOptio
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14221,6 +14964,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
         }
         out.ranges_support = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14236,6 +14982,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentRangeFormatting
         }
         members.push_back(json::Builder::Member{"rangesSupport", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14300,6 +15050,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] RenameOptions
         }
         out.prepare_provider = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14315,6 +15068,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const RenameOptions& in,
         }
         members.push_back(json::Builder::Member{"prepareProvider", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -14329,6 +15086,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ExecuteComman
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressOptions&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -14344,6 +15104,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const ExecuteCommandOptions&
         }
         members.push_back(json::Builder::Member{"commands", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressOptions&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -15714,7 +16478,7 @@ Result<const json::Value*> Encode([[maybe_unused]] const TextDocumentChangeRegis
         }
         members.push_back(json::Builder::Member{"syncKind", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -15815,11 +16579,11 @@ Result<const json::Value*> Encode([[maybe_unused]] const TextDocumentSaveRegistr
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const SaveOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const SaveOptions&>(in), b, members); res != Success) {
         return res.Failure();
     }
 
@@ -16150,6 +16914,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CompletionPar
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -16165,7 +16935,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const CompletionParams& in,
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -16864,11 +17643,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const CompletionRegistrationO
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const CompletionOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const CompletionOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -16879,6 +17659,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] HoverParams&
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -16887,7 +17670,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const HoverParams& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -16956,11 +17744,11 @@ Result<const json::Value*> Encode([[maybe_unused]] const HoverRegistrationOption
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const HoverOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const HoverOptions&>(in), b, members); res != Success) {
         return res.Failure();
     }
 
@@ -17258,6 +18046,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] SignatureHelp
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17273,7 +18064,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const SignatureHelpParams& in
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17296,11 +18092,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const SignatureHelpRegistrati
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const SignatureHelpOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const SignatureHelpOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17311,6 +18108,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DefinitionPar
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17319,7 +18122,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const DefinitionParams& in,
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17342,11 +18154,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const DefinitionRegistrationO
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DefinitionOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DefinitionOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17395,6 +18208,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ReferencePara
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17410,7 +18229,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const ReferenceParams& in,
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17433,11 +18261,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const ReferenceRegistrationOp
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const ReferenceOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ReferenceOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17448,6 +18277,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentHighl
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17456,7 +18291,16 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentHighlightParams
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17526,11 +18370,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentHighlightRegist
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentHighlightOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DocumentHighlightOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17547,6 +18392,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentSymbo
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17562,6 +18413,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentSymbolParams& i
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -17693,7 +18552,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const SymbolInformation& in,
         }
         members.push_back(json::Builder::Member{"location", res.Get()});
     }
-    if (auto res = Encode(static_cast<const BaseSymbolInformation&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const BaseSymbolInformation&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17865,11 +18725,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentSymbolRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentSymbolOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DocumentSymbolOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -17969,6 +18830,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CodeActionPar
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -17998,6 +18865,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeActionParams& in,
         }
         members.push_back(json::Builder::Member{"context", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18202,11 +19077,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeActionRegistrationO
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const CodeActionOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const CodeActionOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18223,6 +19099,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkspaceSymb
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18238,6 +19120,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceSymbolParams&
         }
         members.push_back(json::Builder::Member{"query", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18317,7 +19207,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceSymbol& in,
         }
         members.push_back(json::Builder::Member{"data", res.Get()});
     }
-    if (auto res = Encode(static_cast<const BaseSymbolInformation&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const BaseSymbolInformation&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18337,7 +19228,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceSymbolRegistra
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const WorkspaceSymbolOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const WorkspaceSymbolOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18354,6 +19246,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] CodeLensParam
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18369,6 +19267,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeLensParams& in,
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18454,11 +19360,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const CodeLensRegistrationOpt
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const CodeLensOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const CodeLensOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18475,6 +19382,12 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentLinkP
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
+    if (auto res = Decode(v, static_cast<PartialResultParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18490,6 +19403,14 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentLinkParams& in,
         }
         members.push_back(json::Builder::Member{"textDocument", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const PartialResultParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18593,11 +19514,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentLinkRegistratio
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentLinkOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DocumentLinkOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18722,6 +19644,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] DocumentForma
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18744,6 +19669,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentFormattingParam
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18764,11 +19693,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentFormattingRegis
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentFormattingOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const DocumentFormattingOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -18804,6 +19734,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18833,6 +19766,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentRangeFormatting
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -18854,11 +19791,11 @@ Result<const json::Value*> Encode(
     [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentRangeFormattingOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const DocumentRangeFormattingOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -18895,6 +19832,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v,
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -18924,6 +19864,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const DocumentRangesFormattin
         }
         members.push_back(json::Builder::Member{"options", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -19023,11 +19967,11 @@ Result<const json::Value*> Encode(
     [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const DocumentOnTypeFormattingOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const DocumentOnTypeFormattingOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -19063,6 +20007,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] RenameParams&
             return res.Failure();
         }
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -19092,6 +20039,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const RenameParams& in,
         }
         members.push_back(json::Builder::Member{"newName", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -19111,11 +20062,11 @@ Result<const json::Value*> Encode([[maybe_unused]] const RenameRegistrationOptio
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentRegistrationOptions&>(in), b);
+    if (auto res = EncodeBase(static_cast<const TextDocumentRegistrationOptions&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
-    if (auto res = Encode(static_cast<const RenameOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const RenameOptions&>(in), b, members); res != Success) {
         return res.Failure();
     }
 
@@ -19126,6 +20077,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] PrepareRename
     if (auto res = Decode(v, static_cast<TextDocumentPositionParams&>(out)); res != Success) {
         return res.Failure();
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -19134,7 +20088,12 @@ Result<const json::Value*> Encode([[maybe_unused]] const PrepareRenameParams& in
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const TextDocumentPositionParams&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const TextDocumentPositionParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -19162,6 +20121,9 @@ Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] ExecuteComman
         }
         out.arguments = std::move(val);
     }
+    if (auto res = Decode(v, static_cast<WorkDoneProgressParams&>(out)); res != Success) {
+        return res.Failure();
+    }
 
     return Success;
 }
@@ -19184,6 +20146,10 @@ Result<const json::Value*> Encode([[maybe_unused]] const ExecuteCommandParams& i
         }
         members.push_back(json::Builder::Member{"arguments", res.Get()});
     }
+    if (auto res = EncodeBase(static_cast<const WorkDoneProgressParams&>(in), b, members);
+        res != Success) {
+        return res.Failure();
+    }
 
     return b.Object(members);
 }
@@ -19201,7 +20167,8 @@ Result<const json::Value*> Encode([[maybe_unused]] const ExecuteCommandRegistrat
                                   [[maybe_unused]] json::Builder& b) {
     std::vector<json::Builder::Member> members;
     members.reserve(0);
-    if (auto res = Encode(static_cast<const ExecuteCommandOptions&>(in), b); res != Success) {
+    if (auto res = EncodeBase(static_cast<const ExecuteCommandOptions&>(in), b, members);
+        res != Success) {
         return res.Failure();
     }
 
@@ -19663,68 +20630,6 @@ Result<const json::Value*> Encode([[maybe_unused]] const ProgressParams& in,
     return b.Object(members);
 }
 
-Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] WorkDoneProgressParams& out) {
-    if (v.Has("workDoneToken")) {
-        lsp::ProgressToken val;
-        auto member = v.Get("workDoneToken");
-        if (member != Success) {
-            return member.Failure();
-        }
-        if (auto res = Decode(*member.Get(), val); res != Success) {
-            return res.Failure();
-        }
-        out.work_done_token = std::move(val);
-    }
-
-    return Success;
-}
-
-Result<const json::Value*> Encode([[maybe_unused]] const WorkDoneProgressParams& in,
-                                  [[maybe_unused]] json::Builder& b) {
-    std::vector<json::Builder::Member> members;
-    members.reserve(1);
-    if (in.work_done_token) {
-        auto res = Encode(*in.work_done_token, b);
-        if (res != Success) {
-            return res.Failure();
-        }
-        members.push_back(json::Builder::Member{"workDoneToken", res.Get()});
-    }
-
-    return b.Object(members);
-}
-
-Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] PartialResultParams& out) {
-    if (v.Has("partialResultToken")) {
-        lsp::ProgressToken val;
-        auto member = v.Get("partialResultToken");
-        if (member != Success) {
-            return member.Failure();
-        }
-        if (auto res = Decode(*member.Get(), val); res != Success) {
-            return res.Failure();
-        }
-        out.partial_result_token = std::move(val);
-    }
-
-    return Success;
-}
-
-Result<const json::Value*> Encode([[maybe_unused]] const PartialResultParams& in,
-                                  [[maybe_unused]] json::Builder& b) {
-    std::vector<json::Builder::Member> members;
-    members.reserve(1);
-    if (in.partial_result_token) {
-        auto res = Encode(*in.partial_result_token, b);
-        if (res != Success) {
-            return res.Failure();
-        }
-        members.push_back(json::Builder::Member{"partialResultToken", res.Get()});
-    }
-
-    return b.Object(members);
-}
-
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] LocationLink& out) {
     if (v.Has("originSelectionRange")) {
         lsp::Range val;
@@ -19804,37 +20709,6 @@ Result<const json::Value*> Encode([[maybe_unused]] const LocationLink& in,
     return b.Object(members);
 }
 
-Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] StaticRegistrationOptions& out) {
-    if (v.Has("id")) {
-        String val;
-        auto member = v.Get("id");
-        if (member != Success) {
-            return member.Failure();
-        }
-        if (auto res = Decode(*member.Get(), val); res != Success) {
-            return res.Failure();
-        }
-        out.id = std::move(val);
-    }
-
-    return Success;
-}
-
-Result<const json::Value*> Encode([[maybe_unused]] const StaticRegistrationOptions& in,
-                                  [[maybe_unused]] json::Builder& b) {
-    std::vector<json::Builder::Member> members;
-    members.reserve(1);
-    if (in.id) {
-        auto res = Encode(*in.id, b);
-        if (res != Success) {
-            return res.Failure();
-        }
-        members.push_back(json::Builder::Member{"id", res.Get()});
-    }
-
-    return b.Object(members);
-}
-
 Result<SuccessType> Decode([[maybe_unused]] V& v, [[maybe_unused]] InlineValueText& out) {
     {
         auto member = v.Get("range");
@@ -20024,7 +20898,7 @@ Result<const json::Value*> Encode([[maybe_unused]] const RelatedFullDocumentDiag
         }
         members.push_back(json::Builder::Member{"relatedDocuments", res.Get()});
     }
-    if (auto res = Encode(static_cast<const FullDocumentDiagnosticReport&>(in), b);
+    if (auto res = EncodeBase(static_cast<const FullDocumentDiagnosticReport&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -20067,7 +20941,8 @@ Result<const json::Value*> Encode(
         }
         members.push_back(json::Builder::Member{"relatedDocuments", res.Get()});
     }
-    if (auto res = Encode(static_cast<const UnchangedDocumentDiagnosticReport&>(in), b);
+    if (auto res =
+            EncodeBase(static_cast<const UnchangedDocumentDiagnosticReport&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -20195,7 +21070,7 @@ Result<const json::Value*> Encode([[maybe_unused]] const WorkspaceFullDocumentDi
         }
         members.push_back(json::Builder::Member{"version", res.Get()});
     }
-    if (auto res = Encode(static_cast<const FullDocumentDiagnosticReport&>(in), b);
+    if (auto res = EncodeBase(static_cast<const FullDocumentDiagnosticReport&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
@@ -20250,7 +21125,8 @@ Result<const json::Value*> Encode(
         }
         members.push_back(json::Builder::Member{"version", res.Get()});
     }
-    if (auto res = Encode(static_cast<const UnchangedDocumentDiagnosticReport&>(in), b);
+    if (auto res =
+            EncodeBase(static_cast<const UnchangedDocumentDiagnosticReport&>(in), b, members);
         res != Success) {
         return res.Failure();
     }
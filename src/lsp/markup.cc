@@ -0,0 +1,547 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/markup.h"
+
+#include <algorithm>
+#include <optional>
+#include <utility>
+
+namespace langsvr::lsp {
+namespace {
+
+bool IsAlpha(char c) {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z');
+}
+
+bool IsTagNameChar(char c) {
+    return IsAlpha(c) || (c >= '0' && c <= '9') || c == '-';
+}
+
+bool IsSpace(char c) {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f';
+}
+
+bool IsPunct(char c) {
+    return (c >= '!' && c <= '/') || (c >= ':' && c <= '@') || (c >= '[' && c <= '`') ||
+           (c >= '{' && c <= '~');
+}
+
+bool IsSchemeChar(char c) {
+    return IsAlpha(c) || (c >= '0' && c <= '9') || c == '+' || c == '.' || c == '-';
+}
+
+/// @returns @p text with the ASCII punctuation that has meaning in Markdown backslash-escaped
+std::string EscapeMarkdown(std::string_view text) {
+    static constexpr std::string_view kSpecial = "\\`*_{}[]()#+-.!<>|~";
+    std::string out;
+    out.reserve(text.size());
+    for (char c : text) {
+        if (kSpecial.find(c) != std::string_view::npos) {
+            out += '\\';
+        }
+        out += c;
+    }
+    return out;
+}
+
+/// @returns @p code as a Markdown fenced code block with the language @p language. The fence is
+/// made longer than any run of backticks in @p code.
+std::string CodeBlock(std::string_view language, std::string_view code) {
+    size_t longest = 0;
+    for (size_t i = 0, run = 0; i < code.size(); i++) {
+        run = code[i] == '`' ? run + 1 : 0;
+        longest = std::max(longest, run);
+    }
+    std::string fence(std::max<size_t>(3, longest + 1), '`');
+    return fence + std::string(language) + "\n" + std::string(code) + "\n" + fence;
+}
+
+/// @returns @p marked as Markdown
+std::string ToMarkdown(const MarkedString& marked) {
+    if (auto* with_language = marked.Get<MarkedStringWithLanguage>()) {
+        return CodeBlock(with_language->language, with_language->value);
+    }
+    return *marked.Get<String>();
+}
+
+/// @returns true if the position @p a is before the position @p b
+bool Before(const Position& a, const Position& b) {
+    return a.line < b.line || (a.line == b.line && a.character < b.character);
+}
+
+/// @returns the offset one past the end of the line starting at @p offset, including the line
+/// terminator
+size_t LineEnd(std::string_view text, size_t offset) {
+    auto end = text.find('\n', offset);
+    return end == std::string_view::npos ? text.size() : end + 1;
+}
+
+/// @returns the number of spaces that indent the line starting at @p offset
+size_t Indentation(std::string_view text, size_t offset) {
+    size_t indent = 0;
+    while (offset + indent < text.size() && text[offset + indent] == ' ') {
+        indent++;
+    }
+    return indent;
+}
+
+/// @returns the length of the code fence that opens the line starting at @p offset, or 0 if the
+/// line does not open a code fence. @p fence is set to the fence character.
+size_t FenceLength(std::string_view text, size_t offset, char& fence) {
+    size_t indent = Indentation(text, offset);
+    if (indent > 3) {
+        return 0;
+    }
+    size_t start = offset + indent;
+    if (start >= text.size() || (text[start] != '`' && text[start] != '~')) {
+        return 0;
+    }
+    size_t end = start;
+    while (end < text.size() && text[end] == text[start]) {
+        end++;
+    }
+    if (end - start < 3) {
+        return 0;
+    }
+    // The info string of a backtick fence cannot contain a backtick
+    if (text[start] == '`' &&
+        text.substr(end, LineEnd(text, end) - end).find('`') != std::string_view::npos) {
+        return 0;
+    }
+    fence = text[start];
+    return end - start;
+}
+
+/// @returns the offset one past the end of the raw HTML starting with the '<' at @p offset, or 0 if
+/// the '<' does not start raw HTML.
+size_t HtmlEnd(std::string_view text, size_t offset) {
+    auto rest = text.substr(offset);
+    auto find = [&](std::string_view terminator) -> size_t {
+        auto end = rest.find(terminator, 1);
+        return end == std::string_view::npos ? 0 : offset + end + terminator.size();
+    };
+    if (rest.starts_with("<!--")) {
+        return find("-->");
+    }
+    if (rest.starts_with("<?")) {
+        return find("?>");
+    }
+    if (rest.starts_with("<!") && rest.size() > 2 && IsAlpha(rest[2])) {
+        return find(">");
+    }
+
+    // Open or closing tag
+    size_t i = rest.starts_with("</") ? 2 : 1;
+    if (i >= rest.size() || !IsAlpha(rest[i])) {
+        return 0;
+    }
+    while (i < rest.size() && IsTagNameChar(rest[i])) {
+        i++;
+    }
+    if (i >= rest.size() || !(IsSpace(rest[i]) || rest[i] == '/' || rest[i] == '>')) {
+        return 0;  // Not a tag, for example an autolink such as <https://example.com>
+    }
+    char quote = 0;
+    for (; i < rest.size(); i++) {
+        char c = rest[i];
+        if (quote) {
+            if (c == quote) {
+                quote = 0;
+            }
+        } else if (c == '"' || c == '\'') {
+            quote = c;
+        } else if (c == '>') {
+            return offset + i + 1;
+        }
+    }
+    return 0;
+}
+
+/// @returns the offset one past the end of the URI autolink starting with the '<' at @p offset, or
+/// 0 if the '<' does not start a URI autolink, such as <https://example.com>.
+size_t AutolinkEnd(std::string_view text, size_t offset) {
+    size_t i = offset + 1;
+    if (i >= text.size() || !IsAlpha(text[i])) {
+        return 0;
+    }
+    while (i < text.size() && IsSchemeChar(text[i])) {
+        i++;
+    }
+    size_t scheme_length = i - offset - 1;
+    if (scheme_length < 2 || scheme_length > 32 || i >= text.size() || text[i] != ':') {
+        return 0;
+    }
+    for (i++; i < text.size(); i++) {
+        auto c = static_cast<unsigned char>(text[i]);
+        if (c == '>') {
+            return i + 1;
+        }
+        if (c <= ' ' || c == '<' || c == 0x7f) {
+            return 0;
+        }
+    }
+    return 0;
+}
+
+/// @returns true if raw HTML or an autolink starts in [begin, end) of @p text, and ends after it.
+/// Raw HTML and autolinks take precedence over a code span holding their start.
+bool HtmlCrosses(std::string_view text, size_t begin, size_t end) {
+    for (auto lt = text.find('<', begin); lt < end; lt = text.find('<', lt + 1)) {
+        if (HtmlEnd(text, lt) > end || AutolinkEnd(text, lt) > end) {
+            return true;
+        }
+    }
+    return false;
+}
+
+/// @returns true if the link destination @p destination is relative, or uses the http, https or
+/// mailto scheme.
+bool IsSafeDestination(std::string_view destination) {
+    // Resolve the backslash escapes, and drop the whitespace and control characters, which browsers
+    // ignore in some parts of a URL.
+    std::string url;
+    for (size_t i = 0; i < destination.size(); i++) {
+        char c = destination[i];
+        if (c == '\\' && i + 1 < destination.size() && IsPunct(destination[i + 1])) {
+            url += destination[++i];
+        } else if (static_cast<unsigned char>(c) > ' ' && c != 0x7f) {
+            url += c;
+        }
+    }
+    auto end = url.find_first_of(":/?#");
+    std::string scheme = url.substr(0, end);
+    if (scheme.find('&') != std::string::npos) {
+        return false;  // The scheme may be hidden by an entity, such as 'javascript&colon;'
+    }
+    if (end == std::string::npos || url[end] != ':') {
+        return true;  // Relative
+    }
+    std::transform(scheme.begin(), scheme.end(), scheme.begin(),
+                   [](char c) { return (c >= 'A' && c <= 'Z') ? c - 'A' + 'a' : c; });
+    return scheme == "http" || scheme == "https" || scheme == "mailto";
+}
+
+/// Destination is a link destination
+struct Destination {
+    /// The offset of the start of the destination, including any '<'
+    size_t begin = 0;
+    /// The offset one past the end of the destination, including any '>'
+    size_t end = 0;
+    /// The destination, without any angle brackets
+    std::string_view url;
+};
+
+/// @returns the link destination that follows the whitespace at @p offset of @p text, or
+/// std::nullopt if there is none. The whitespace can hold a single line break.
+std::optional<Destination> ParseDestination(std::string_view text, size_t offset) {
+    bool line_break = false;
+    for (; offset < text.size() && IsSpace(text[offset]); offset++) {
+        if (text[offset] == '\n') {
+            if (line_break) {
+                return std::nullopt;
+            }
+            line_break = true;
+        }
+    }
+    if (offset >= text.size()) {
+        return std::nullopt;
+    }
+
+    size_t begin = offset;
+    if (text[begin] == '<') {
+        for (size_t i = begin + 1; i < text.size(); i++) {
+            char c = text[i];
+            if (c == '\\') {
+                i++;
+            } else if (c == '\n' || c == '<') {
+                return std::nullopt;
+            } else if (c == '>') {
+                return Destination{begin, i + 1, text.substr(begin + 1, i - begin - 1)};
+            }
+        }
+        return std::nullopt;
+    }
+
+    // A destination without angle brackets ends at whitespace, or at an unbalanced ')'
+    size_t end = begin;
+    for (int depth = 0; end < text.size(); end++) {
+        auto c = static_cast<unsigned char>(text[end]);
+        if (c == '\\' && end + 1 < text.size() && IsPunct(text[end + 1])) {
+            end++;
+        } else if (c <= ' ' || c == 0x7f) {
+            break;
+        } else if (c == '(') {
+            depth++;
+        } else if (c == ')') {
+            if (depth == 0) {
+                break;
+            }
+            depth--;
+        }
+    }
+    if (end == begin) {
+        return std::nullopt;
+    }
+    return Destination{begin, end, text.substr(begin, end - begin)};
+}
+
+/// @returns the destination of the link reference definition, such as '[label]: https://a.com',
+/// that starts the line at @p offset, or std::nullopt if the line does not start a definition.
+std::optional<Destination> ReferenceDestination(std::string_view text, size_t offset) {
+    size_t i = offset;
+    while (i < offset + 3 && i < text.size() && text[i] == ' ') {
+        i++;
+    }
+    if (i >= text.size() || text[i] != '[') {
+        return std::nullopt;
+    }
+    for (i++; i < text.size(); i++) {
+        if (text[i] == '\\') {
+            i++;
+        } else if (text[i] == '[') {
+            return std::nullopt;
+        } else if (text[i] == ']') {
+            break;
+        }
+    }
+    if (i + 1 >= text.size() || text[i + 1] != ':') {
+        return std::nullopt;
+    }
+    return ParseDestination(text, i + 2);
+}
+
+/// @returns @p markdown with a single pass of raw HTML and unsafe link destinations removed
+std::string SanitizeOnce(std::string_view markdown) {
+    std::string out;
+    out.reserve(markdown.size());
+
+    size_t offset = 0;
+    bool line_start = true;
+    while (offset < markdown.size()) {
+        if (line_start) {
+            line_start = false;
+            char fence = 0;
+            if (size_t length = FenceLength(markdown, offset, fence); length > 0) {
+                // Copy the fenced code block verbatim, up to and including the closing fence.
+                // An indented fence may be nested in a list item, which ends at the first non-blank
+                // line that is indented less than the fence, closing the code block with it.
+                size_t indent = Indentation(markdown, offset);
+                size_t end = LineEnd(markdown, offset);
+                while (end < markdown.size()) {
+                    size_t line_indent = Indentation(markdown, end);
+                    char first =
+                        end + line_indent < markdown.size() ? markdown[end + line_indent] : '\n';
+                    if (line_indent < indent && first != '\n' && first != '\r' && first != '\t') {
+                        break;
+                    }
+                    char closing = 0;
+                    size_t closing_length = FenceLength(markdown, end, closing);
+                    end = LineEnd(markdown, end);
+                    if (closing == fence && closing_length >= length) {
+                        break;
+                    }
+                }
+                out += markdown.substr(offset, end - offset);
+                offset = end;
+                line_start = true;
+                continue;
+            }
+            if (auto dest = ReferenceDestination(markdown, offset);
+                dest && !IsSafeDestination(dest->url)) {
+                // Sanitize the label on its own, and replace the destination with an empty one
+                out += SanitizeOnce(markdown.substr(offset, dest->begin - offset));
+                out += "<>";
+                offset = dest->end;
+                continue;
+            }
+        }
+
+        char c = markdown[offset];
+        switch (c) {
+            case '\n':
+                out += c;
+                offset++;
+                line_start = true;
+                continue;
+            case '\\':
+                // Backslash escape. Copy the escaped character verbatim.
+                out += markdown.substr(offset, 2);
+                offset += 2;
+                continue;
+            case '`': {
+                // Code span. Copy verbatim if there is a closing backtick run of the same length on
+                // the same line.
+                size_t end = offset;
+                while (end < markdown.size() && markdown[end] == '`') {
+                    end++;
+                }
+                auto run = markdown.substr(offset, end - offset);
+                auto line_end = markdown.find('\n', end);
+                for (auto close = markdown.find(run, end);
+                     close != std::string_view::npos && close < line_end;
+                     close = markdown.find(run, close + run.size())) {
+                    size_t close_end = close + run.size();
+                    if ((close_end >= markdown.size() || markdown[close_end] != '`') &&
+                        markdown[close - 1] != '`') {
+                        if (!HtmlCrosses(markdown, end, close)) {
+                            end = close_end;
+                        }
+                        break;
+                    }
+                }
+                out += markdown.substr(offset, end - offset);
+                offset = end;
+                continue;
+            }
+            case '<':
+                if (size_t end = HtmlEnd(markdown, offset); end > 0) {
+                    offset = end;
+                    continue;
+                }
+                if (size_t end = AutolinkEnd(markdown, offset);
+                    end > 0 && !IsSafeDestination(markdown.substr(offset + 1, end - offset - 2))) {
+                    // Escape the '<', so that the autolink is rendered as text
+                    out += "\\<";
+                    offset++;
+                    continue;
+                }
+                break;
+            case ']':
+                // Inline link or image. Replace an unsafe destination with an empty one.
+                if (offset + 1 < markdown.size() && markdown[offset + 1] == '(') {
+                    if (auto dest = ParseDestination(markdown, offset + 2);
+                        dest && !IsSafeDestination(dest->url)) {
+                        out += markdown.substr(offset, dest->begin - offset);
+                        out += "<>";
+                        offset = dest->end;
+                        continue;
+                    }
+                }
+                break;
+        }
+        out += c;
+        offset++;
+    }
+    return out;
+}
+
+}  // namespace
+
+std::string SanitizeMarkdown(std::string_view markdown) {
+    // Removing a tag can join the text either side of it into a new tag, such as '<<b>script>'.
+    // Repeat until there is nothing more to remove.
+    std::string out = SanitizeOnce(markdown);
+    while (true) {
+        std::string next = SanitizeOnce(out);
+        if (next == out) {
+            return out;
+        }
+        out = std::move(next);
+    }
+}
+
+MarkupContent SanitizeMarkupContent(MarkupContent content) {
+    if (content.kind == MarkupKind::kMarkdown) {
+        content.value = SanitizeMarkdown(content.value);
+    }
+    return content;
+}
+
+Optional<Hover> MergeHovers(const std::vector<Optional<Hover>>& hovers) {
+    std::vector<const Hover*> set;
+    for (auto& hover : hovers) {
+        if (hover) {
+            set.push_back(&*hover);
+        }
+    }
+    if (set.empty()) {
+        return {};
+    }
+
+    bool plaintext = std::all_of(set.begin(), set.end(), [](const Hover* hover) {
+        auto* markup = hover->contents.Get<MarkupContent>();
+        return markup && markup->kind == MarkupKind::kPlainText;
+    });
+
+    Hover out;
+    std::string value;
+    for (auto* hover : set) {
+        std::string part;
+        if (auto* markup = hover->contents.Get<MarkupContent>()) {
+            bool escape = !plaintext && markup->kind == MarkupKind::kPlainText;
+            part = escape ? EscapeMarkdown(markup->value) : markup->value;
+        } else if (auto* marked = hover->contents.Get<MarkedString>()) {
+            part = ToMarkdown(*marked);
+        } else if (auto* list = hover->contents.Get<std::vector<MarkedString>>()) {
+            for (auto& marked : *list) {
+                part += (part.empty() ? "" : "\n\n") + ToMarkdown(marked);
+            }
+        }
+        if (!value.empty()) {
+            value += "\n\n---\n\n";
+        }
+        value += part;
+
+        if (hover->range) {
+            if (!out.range) {
+                out.range = *hover->range;
+            } else {
+                if (Before(out.range->start, hover->range->start)) {
+                    out.range->start = hover->range->start;
+                }
+                if (Before(hover->range->end, out.range->end)) {
+                    out.range->end = hover->range->end;
+                }
+            }
+        }
+    }
+
+    if (out.range && Before(out.range->end, out.range->start)) {
+        out.range.Reset();  // The ranges do not overlap
+    }
+    out.contents = MarkupContent{plaintext ? MarkupKind::kPlainText : MarkupKind::kMarkdown,
+                                 std::move(value)};
+    return out;
+}
+
+Result<Hover> HoverWithRange(MarkupContent content,
+                             std::string_view document,
+                             const Position& position,
+                             PositionEncodingKind encoding,
+                             const WordPredicate& is_word) {
+    auto range = WordRangeAt(document, position, encoding, is_word);
+    if (range != Success) {
+        return range.Failure();
+    }
+    Hover out;
+    out.contents = std::move(content);
+    out.range = range.Move();
+    return out;
+}
+
+}  // namespace langsvr::lsp
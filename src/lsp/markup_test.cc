@@ -0,0 +1,220 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/markup.h"
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+TEST(MarkupTest, RemovesTags) {
+    EXPECT_EQ(SanitizeMarkdown("a <script>alert(1)</script> b"), "a alert(1) b");
+    EXPECT_EQ(SanitizeMarkdown("<img src=x onerror=\"alert('>')\">text"), "text");
+    EXPECT_EQ(SanitizeMarkdown("<a\nhref='x'>link</a>"), "link");
+    EXPECT_EQ(SanitizeMarkdown("x<br/>y"), "xy");
+}
+
+TEST(MarkupTest, RemovesCommentsAndDeclarations) {
+    EXPECT_EQ(SanitizeMarkdown("a<!-- <script> -->b"), "ab");
+    EXPECT_EQ(SanitizeMarkdown("<!DOCTYPE html>a<?php echo 1 ?>b"), "ab");
+}
+
+TEST(MarkupTest, RemovesNestedTags) {
+    EXPECT_EQ(SanitizeMarkdown("<<b>script>alert(1)<</b>/script>"), "alert(1)");
+}
+
+TEST(MarkupTest, EscapesUnsafeAutolinks) {
+    EXPECT_EQ(SanitizeMarkdown("<javascript:alert(1)>"), "\\<javascript:alert(1)>");
+    EXPECT_EQ(SanitizeMarkdown("<JavaScript:alert(1)>"), "\\<JavaScript:alert(1)>");
+    EXPECT_EQ(SanitizeMarkdown("<data:text/html,x>"), "\\<data:text/html,x>");
+    EXPECT_EQ(SanitizeMarkdown("<vbscript:x> and <https://a.com>"),
+              "\\<vbscript:x> and <https://a.com>");
+}
+
+TEST(MarkupTest, RemovesUnsafeLinkDestinations) {
+    EXPECT_EQ(SanitizeMarkdown("[x](javascript:alert(1))"), "[x](<>)");
+    EXPECT_EQ(SanitizeMarkdown("[x](javascript:alert(1) \"title\")"), "[x](<> \"title\")");
+    EXPECT_EQ(SanitizeMarkdown("[x]( <javascript:alert(1)>)"), "[x]( <>)");
+    EXPECT_EQ(SanitizeMarkdown("![x](data:image/svg+xml,y)"), "![x](<>)");
+    EXPECT_EQ(SanitizeMarkdown("[x](java\\script:alert(1))"), "[x](<>)");
+    EXPECT_EQ(SanitizeMarkdown("[x](javascript&colon;alert(1))"), "[x](<>)");
+    EXPECT_EQ(SanitizeMarkdown("[x](\njavascript:alert(1))"), "[x](\n<>)");
+}
+
+TEST(MarkupTest, RemovesUnsafeReferenceDestinations) {
+    EXPECT_EQ(SanitizeMarkdown("[x]\n\n[x]: javascript:alert(1)"), "[x]\n\n[x]: <>");
+    EXPECT_EQ(SanitizeMarkdown("[x]\n\n  [<b>x</b>]:\n<data:x> 'title'"),
+              "[x]\n\n  [x]:\n<> 'title'");
+}
+
+TEST(MarkupTest, PreservesSafeLinks) {
+    for (auto markdown : {
+             "[x](https://example.com/a:b)",
+             "[x](HTTP://example.com)",
+             "[x](mailto:user@example.com)",
+             "[x](relative/path:with-colon)",
+             "[x](#anchor) and [y](?q=a:b)",
+             "[x]: https://example.com",
+             "`<javascript:alert(1)>` and `[x](javascript:y)`",
+         }) {
+        EXPECT_EQ(SanitizeMarkdown(markdown), markdown);
+    }
+}
+
+TEST(MarkupTest, PreservesMarkdown) {
+    for (auto markdown : {
+             "# Title\n\n*emphasis* and **strong** [link](https://example.com)",
+             "<https://example.com> and <user@example.com>",
+             "a < b && c > d",
+             "1 <2 and x<-y",
+             "\\<script> is escaped",
+             "unclosed <b",
+         }) {
+        EXPECT_EQ(SanitizeMarkdown(markdown), markdown);
+    }
+}
+
+TEST(MarkupTest, PreservesCode) {
+    for (auto markdown : {
+             "use `<script>` tags",
+             "use ``a ` <b>`` tags",
+             "```html\n<script>alert(1)</script>\n```\n",
+             "~~~~\n<b>\n~~~\n</b>\n~~~~",
+         }) {
+        EXPECT_EQ(SanitizeMarkdown(markdown), markdown);
+    }
+    EXPECT_EQ(SanitizeMarkdown("```\n<b>\n```\n<b>after</b>"), "```\n<b>\n```\nafter");
+    EXPECT_EQ(SanitizeMarkdown("unclosed `<b>code"), "unclosed `code");
+    EXPECT_EQ(SanitizeMarkdown("`<b title='`'>x`"), "`x`");
+    EXPECT_EQ(SanitizeMarkdown("`<javascript:`>"), "`\\<javascript:`>");
+
+    // A backtick in the info string means the line does not open a fence
+    EXPECT_EQ(SanitizeMarkdown("``` a`b\n<script>alert(1)</script>\n"), "``` a`b\nalert(1)\n");
+    // A fence in a list item is closed when the list item ends
+    EXPECT_EQ(SanitizeMarkdown("- a\n  ```\n<script>alert(1)</script>\n"),
+              "- a\n  ```\nalert(1)\n");
+    EXPECT_EQ(SanitizeMarkdown("- a\n  ```\n  <b>\n\n  ```\n"), "- a\n  ```\n  <b>\n\n  ```\n");
+}
+
+TEST(MarkupTest, SanitizeMarkupContent) {
+    auto markdown = SanitizeMarkupContent(MarkupContent{MarkupKind::kMarkdown, "<b>x</b>"});
+    EXPECT_EQ(markdown.kind, MarkupKind::kMarkdown);
+    EXPECT_EQ(markdown.value, "x");
+
+    auto plain = SanitizeMarkupContent(MarkupContent{MarkupKind::kPlainText, "<b>x</b>"});
+    EXPECT_EQ(plain.value, "<b>x</b>");
+}
+
+TEST(MarkupTest, MergeHoversNone) {
+    EXPECT_FALSE(MergeHovers({}));
+    EXPECT_FALSE(MergeHovers({Optional<Hover>{}, Optional<Hover>{}}));
+}
+
+TEST(MarkupTest, MergeHoversPlainText) {
+    Hover a;
+    a.contents = MarkupContent{MarkupKind::kPlainText, "int x"};
+    Hover b;
+    b.contents = MarkupContent{MarkupKind::kPlainText, "a *variable*"};
+
+    auto got = MergeHovers({a, Optional<Hover>{}, b});
+    ASSERT_TRUE(got);
+    auto* content = got->contents.Get<MarkupContent>();
+    ASSERT_NE(content, nullptr);
+    EXPECT_EQ(content->kind, MarkupKind::kPlainText);
+    EXPECT_EQ(content->value, "int x\n\n---\n\na *variable*");
+    EXPECT_FALSE(got->range);
+}
+
+TEST(MarkupTest, MergeHoversMixed) {
+    Hover markdown;
+    markdown.contents = MarkupContent{MarkupKind::kMarkdown, "**bold**"};
+    Hover plain;
+    plain.contents = MarkupContent{MarkupKind::kPlainText, "a_b"};
+    Hover marked;
+    marked.contents = MarkedString{MarkedStringWithLanguage{"cpp", "int x = `y`;"}};
+    Hover list;
+    list.contents = std::vector<MarkedString>{String{"one"}, String{"two"}};
+
+    auto got = MergeHovers({markdown, plain, marked, list});
+    ASSERT_TRUE(got);
+    auto* content = got->contents.Get<MarkupContent>();
+    ASSERT_NE(content, nullptr);
+    EXPECT_EQ(content->kind, MarkupKind::kMarkdown);
+    EXPECT_EQ(content->value,
+              "**bold**\n\n---\n\n"
+              "a\\_b\n\n---\n\n"
+              "```cpp\nint x = `y`;\n```\n\n---\n\n"
+              "one\n\ntwo");
+}
+
+TEST(MarkupTest, MergeHoversRange) {
+    auto hover = [](Uinteger start, Uinteger end) {
+        Hover out;
+        out.contents = MarkupContent{MarkupKind::kPlainText, "x"};
+        out.range = Range{Position{0, start}, Position{0, end}};
+        return out;
+    };
+
+    auto overlapping = MergeHovers({hover(2, 10), Hover{}, hover(4, 12)});
+    ASSERT_TRUE(overlapping);
+    ASSERT_TRUE(overlapping->range);
+    EXPECT_EQ(overlapping->range->start.character, 4u);
+    EXPECT_EQ(overlapping->range->end.character, 10u);
+
+    auto disjoint = MergeHovers({hover(2, 4), hover(6, 8)});
+    ASSERT_TRUE(disjoint);
+    EXPECT_FALSE(disjoint->range);
+}
+
+TEST(MarkupTest, HoverWithRange) {
+    auto hover = HoverWithRange(MarkupContent{MarkupKind::kPlainText, "doc"}, "int foo_bar = 1;",
+                                Position{0, 6});
+    ASSERT_EQ(hover, Success);
+    auto content = hover.Get().contents.Get<MarkupContent>();
+    ASSERT_NE(content, nullptr);
+    EXPECT_EQ(content->value, "doc");
+    ASSERT_TRUE(hover.Get().range);
+    EXPECT_EQ(hover.Get().range->start.character, 4u);
+    EXPECT_EQ(hover.Get().range->end.character, 11u);
+}
+
+TEST(MarkupTest, HoverWithRangeNotOnWord) {
+    auto hover = HoverWithRange(MarkupContent{MarkupKind::kPlainText, "doc"}, "a  =  b",
+                                Position{0, 3});
+    ASSERT_EQ(hover, Success);
+    EXPECT_FALSE(hover.Get().range);
+}
+
+TEST(MarkupTest, HoverWithRangeInvalidPosition) {
+    auto hover = HoverWithRange(MarkupContent{MarkupKind::kPlainText, "doc"}, "abc",
+                                Position{3, 0});
+    EXPECT_NE(hover, Success);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
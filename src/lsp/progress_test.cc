@@ -0,0 +1,214 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/progress.h"
+
+#include <set>
+#include <string>
+#include <type_traits>
+#include <vector>
+
+#include "gmock/gmock.h"
+#include "langsvr/session.h"
+
+namespace langsvr::lsp {
+namespace {
+
+// Requests hold their work done and partial result tokens as ProgressTokens
+static_assert(
+    std::is_same_v<decltype(InitializeRequest::work_done_token), Optional<ProgressToken>>);
+static_assert(
+    std::is_same_v<decltype(TextDocumentHoverRequest::work_done_token), Optional<ProgressToken>>);
+static_assert(std::is_same_v<decltype(TextDocumentReferencesRequest::partial_result_token),
+                             Optional<ProgressToken>>);
+
+TEST(ProgressTest, NewProgressToken) {
+    std::set<std::string> seen;
+    for (int i = 0; i < 100; i++) {
+        auto token = NewProgressToken();
+        ASSERT_TRUE(token.Is<String>());
+        auto& uuid = *token.Get<String>();
+        ASSERT_EQ(uuid.size(), 36u);
+        EXPECT_EQ(uuid[8], '-');
+        EXPECT_EQ(uuid[13], '-');
+        EXPECT_EQ(uuid[14], '4');
+        EXPECT_EQ(uuid[18], '-');
+        EXPECT_THAT(std::string("89ab"), testing::HasSubstr(std::string(1, uuid[19])));
+        EXPECT_EQ(uuid[23], '-');
+        EXPECT_TRUE(seen.emplace(uuid).second);
+    }
+}
+
+TEST(ProgressTest, RoundTrip) {
+    WorkDoneProgressBegin begin;
+    begin.title = "Indexing";
+    begin.message = "src/";
+    begin.percentage = 10;
+    auto notification = MakeProgressNotification(ProgressToken{String{"token"}}, begin);
+    ASSERT_EQ(notification, Success);
+    ASSERT_TRUE(notification.Get().token.Is<String>());
+    EXPECT_EQ(*notification.Get().token.Get<String>(), "token");
+
+    auto decoded = DecodeProgressValue<WorkDoneProgressBegin>(notification.Get());
+    ASSERT_EQ(decoded, Success);
+    EXPECT_EQ(decoded.Get().title, "Indexing");
+    EXPECT_EQ(decoded.Get().message, "src/");
+    EXPECT_EQ(decoded.Get().percentage, 10u);
+}
+
+TEST(ProgressTest, DecodePhase) {
+    WorkDoneProgressReport report;
+    report.percentage = 50;
+    auto notification = MakeProgressNotification(ProgressToken{Integer{1}}, report);
+    ASSERT_EQ(notification, Success);
+
+    auto decoded = DecodeProgressValue<WorkDoneProgress>(notification.Get());
+    ASSERT_EQ(decoded, Success);
+    ASSERT_TRUE(decoded.Get().Is<WorkDoneProgressReport>());
+    EXPECT_EQ(decoded.Get().Get<WorkDoneProgressReport>()->percentage, 50u);
+
+    EXPECT_NE(DecodeProgressValue<WorkDoneProgressEnd>(notification.Get()), Success);
+}
+
+TEST(ProgressTest, DecodeUnknownKind) {
+    LSPAny kind;
+    kind.Set(String{"middle"});
+    ProgressParams params;
+    auto any = ToLSPAny(LSPObject{{"kind", kind}});
+    ASSERT_EQ(any, Success);
+    params.value = any.Move();
+    EXPECT_NE(DecodeProgressValue<WorkDoneProgress>(params), Success);
+}
+
+TEST(ProgressTest, SendProgress) {
+    ServerSession server;
+    ClientSession client;
+    server.SetSender([&](std::string_view msg) { return client.Receive(msg); });
+
+    std::vector<std::string> received;
+    client.Register([&](const ProgressNotification& notification) -> Result<SuccessType> {
+        auto value = DecodeProgressValue<WorkDoneProgress>(notification);
+        if (value != Success) {
+            return value.Failure();
+        }
+        value.Get().Visit([&](auto& v) { received.push_back(std::string(v.kKind)); });
+        return Success;
+    });
+
+    WorkDoneProgressBegin begin;
+    begin.title = "Indexing";
+    EXPECT_EQ(SendProgress(server, ProgressToken{Integer{1}}, begin), Success);
+    EXPECT_EQ(SendProgress(server, ProgressToken{Integer{1}}, WorkDoneProgressReport{}), Success);
+    WorkDoneProgress end = WorkDoneProgressEnd{};
+    EXPECT_EQ(SendProgress(server, ProgressToken{Integer{1}}, end), Success);
+    EXPECT_THAT(received, testing::ElementsAre("begin", "report", "end"));
+}
+
+TEST(ProgressTest, WorkDoneProgressReporter) {
+    ServerSession server;
+    ClientSession client;
+    server.SetSender([&](std::string_view msg) { return client.Receive(msg); });
+
+    std::vector<std::string> received;
+    client.Register([&](const ProgressNotification& notification) -> Result<SuccessType> {
+        EXPECT_TRUE(notification.token.Is<String>());
+        auto value = DecodeProgressValue<WorkDoneProgress>(notification);
+        if (value != Success) {
+            return value.Failure();
+        }
+        value.Get().Visit([&](auto& v) {
+            received.push_back(std::string(v.kKind) + ": " + (v.message ? *v.message : ""));
+        });
+        return Success;
+    });
+
+    WorkDoneProgressParams params;
+    params.work_done_token = ProgressToken{String{"init"}};
+    WorkDoneProgressReporter reporter(server, params.work_done_token);
+    EXPECT_TRUE(reporter.Enabled());
+    EXPECT_EQ(reporter.Begin("Initializing", String{"a"}, Uinteger{0}), Success);
+    EXPECT_EQ(reporter.Report(String{"b"}, Uinteger{50}), Success);
+    EXPECT_EQ(reporter.End(String{"c"}), Success);
+    EXPECT_THAT(received, testing::ElementsAre("begin: a", "report: b", "end: c"));
+}
+
+TEST(ProgressTest, WorkDoneProgressReporterInitializeToken) {
+    static constexpr std::string_view kInitializeMsg =
+        R"({"jsonrpc":"2.0","id":1,"method":"initialize","params":{"processId":1,"rootUri":null,"capabilities":{},"workDoneToken":"init-token"}})";
+
+    ServerSession server;
+    ClientSession client;
+    std::vector<std::string> responses;
+    server.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        if (msg.find("$/progress") != std::string_view::npos) {
+            return client.Receive(msg);
+        }
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    std::vector<std::string> received;
+    client.Register([&](const ProgressNotification& notification) -> Result<SuccessType> {
+        EXPECT_EQ(*notification.token.Get<String>(), "init-token");
+        auto value = DecodeProgressValue<WorkDoneProgress>(notification);
+        if (value != Success) {
+            return value.Failure();
+        }
+        value.Get().Visit([&](auto& v) { received.push_back(std::string(v.kKind)); });
+        return Success;
+    });
+
+    server.Register([&](const InitializeRequest& init) -> InitializeResult {
+        WorkDoneProgressReporter reporter(server, init.work_done_token);
+        EXPECT_TRUE(reporter.Enabled());
+        EXPECT_EQ(reporter.Begin("Initializing"), Success);
+        EXPECT_EQ(reporter.End(), Success);
+        return InitializeResult{};
+    });
+
+    EXPECT_EQ(server.Receive(kInitializeMsg), Success);
+    EXPECT_THAT(received, testing::ElementsAre("begin", "end"));
+    EXPECT_EQ(responses.size(), 1u);
+}
+
+TEST(ProgressTest, WorkDoneProgressReporterNoToken) {
+    ServerSession server;
+    server.SetSender([&](std::string_view) -> Result<SuccessType> {
+        ADD_FAILURE() << "progress sent without a work done token";
+        return Success;
+    });
+
+    WorkDoneProgressParams params;
+    WorkDoneProgressReporter reporter(server, params.work_done_token);
+    EXPECT_FALSE(reporter.Enabled());
+    EXPECT_EQ(reporter.Begin("Initializing"), Success);
+    EXPECT_EQ(reporter.Report(String{"b"}), Success);
+    EXPECT_EQ(reporter.End(), Success);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,75 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/registration.h"
+
+#include <type_traits>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+static_assert(Registrable<TextDocumentSignatureHelpRequest>);
+static_assert(Registrable<TextDocumentDidChangeNotification>);
+static_assert(!Registrable<ShutdownRequest>);
+
+static_assert(std::is_same_v<TextDocumentDidChangeNotification::RegistrationOptions,
+                             TextDocumentChangeRegistrationOptions>);
+static_assert(TextDocumentSemanticTokensFullDeltaRequest::kRegistrationMethod ==
+              "textDocument/semanticTokens");
+
+template <typename MESSAGE, typename OPTIONS>
+concept CanMakeRegistration =
+    requires(OPTIONS options) { MakeRegistration<MESSAGE>("id", options); };
+
+static_assert(
+    CanMakeRegistration<TextDocumentSignatureHelpRequest, SignatureHelpRegistrationOptions>);
+static_assert(!CanMakeRegistration<TextDocumentSignatureHelpRequest, Registration>);
+static_assert(!CanMakeRegistration<ShutdownRequest, SignatureHelpRegistrationOptions>);
+
+TEST(RegistrationTest, MakeRegistration) {
+    SignatureHelpRegistrationOptions options;
+    options.document_selector = Null{};
+    options.trigger_characters = std::vector<String>{"(", ","};
+
+    auto registration =
+        MakeRegistration<TextDocumentSignatureHelpRequest>("signature-help", options);
+    ASSERT_EQ(registration, Success);
+    EXPECT_EQ(registration.Get().id, "signature-help");
+    EXPECT_EQ(registration.Get().method, "textDocument/signatureHelp");
+    ASSERT_TRUE(registration.Get().register_options);
+
+    SignatureHelpRegistrationOptions decoded;
+    ASSERT_EQ(FromLSPAny(*registration.Get().register_options, decoded), Success);
+    EXPECT_TRUE(decoded.document_selector.Is<Null>());
+    ASSERT_TRUE(decoded.trigger_characters);
+    EXPECT_THAT(*decoded.trigger_characters, testing::ElementsAre("(", ","));
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,289 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/semantic_tokens.h"
+
+#include <algorithm>
+#include <sstream>
+#include <string>
+#include <string_view>
+#include <unordered_map>
+#include <unordered_set>
+#include <utility>
+
+#include "langsvr/json/builder.h"
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// The number of integers used to encode each token
+static constexpr size_t kIntsPerToken = 5;
+
+/// The maximum number of token modifiers that can be held in the modifier bitset. The protocol's
+/// uinteger is 32 bits, even though Uinteger is wider.
+static constexpr size_t kMaxModifiers = 32;
+
+/// The maximum number of integers that SemanticTokensStream reserves for a chunk up front
+static constexpr size_t kMaxReservedInts = 64 * 1024;
+
+/// @returns the LSP string of the enumerator @p value
+template <typename ENUM>
+std::string ToString(ENUM value) {
+    auto b = json::Builder::Create();
+    auto encoded = Encode(value, *b);
+    return encoded.Get()->String().Get();
+}
+
+/// @returns the index of @p name in @p list, appending @p name to @p list if it is not found
+Uinteger IndexOf(std::vector<String>& list, std::string_view name) {
+    for (size_t i = 0; i < list.size(); i++) {
+        if (list[i] == name) {
+            return static_cast<Uinteger>(i);
+        }
+    }
+    list.push_back(String(name));
+    return static_cast<Uinteger>(list.size() - 1);
+}
+
+}  // namespace
+
+std::ostream& operator<<(std::ostream& out, const SemanticToken& token) {
+    out << "{" << token.line << ":" << token.character << " len: " << token.length
+        << " type: " << token.type << " modifiers: [";
+    for (size_t i = 0; i < token.modifiers.size(); i++) {
+        out << (i > 0 ? ", " : "") << token.modifiers[i];
+    }
+    return out << "]}";
+}
+
+Result<std::vector<Uinteger>> EncodeSemanticTokens(const SemanticTokensLegend& legend,
+                                                   const std::vector<SemanticToken>& tokens) {
+    std::vector<Uinteger> data;
+    SemanticTokensStream stream(legend, tokens.size() * kIntsPerToken,
+                                [&](SemanticTokensPartialResult&& chunk) {
+                                    data = std::move(chunk.data);
+                                    return Success;
+                                });
+    for (auto& token : tokens) {
+        if (auto res = stream.Add(token); res != Success) {
+            return res.Failure();
+        }
+    }
+    if (auto res = stream.Flush(); res != Success) {
+        return res.Failure();
+    }
+    return data;
+}
+
+SemanticTokensStream::SemanticTokensStream(const SemanticTokensLegend& legend,
+                                           size_t chunk_size,
+                                           ChunkHandler&& on_chunk)
+    : chunk_size_(std::max(chunk_size / kIntsPerToken, size_t{1}) * kIntsPerToken),
+      on_chunk_(std::move(on_chunk)) {
+    for (size_t i = 0; i < legend.token_types.size(); i++) {
+        types_.emplace(legend.token_types[i], i);
+    }
+    for (size_t i = 0; i < legend.token_modifiers.size() && i < kMaxModifiers; i++) {
+        modifiers_.emplace(legend.token_modifiers[i], Uinteger{1} << i);
+    }
+    pending_.reserve(std::min(chunk_size_, kMaxReservedInts));
+}
+
+Result<SuccessType> SemanticTokensStream::Add(const SemanticToken& token) {
+    if (token.line < line_ || (token.line == line_ && token.character < character_)) {
+        std::stringstream err;
+        err << "semantic token " << token << " is not sorted by position";
+        return Failure{err.str()};
+    }
+
+    auto type = types_.find(token.type);
+    if (type == types_.end()) {
+        return Failure{"unknown semantic token type '" + token.type + "'"};
+    }
+
+    Uinteger bits = 0;
+    for (auto& name : token.modifiers) {
+        auto modifier = modifiers_.find(name);
+        if (modifier == modifiers_.end()) {
+            return Failure{"unknown semantic token modifier '" + name + "'"};
+        }
+        bits |= modifier->second;
+    }
+
+    pending_.push_back(token.line - line_);
+    pending_.push_back(token.line == line_ ? token.character - character_ : token.character);
+    pending_.push_back(token.length);
+    pending_.push_back(type->second);
+    pending_.push_back(bits);
+
+    line_ = token.line;
+    character_ = token.character;
+
+    if (pending_.size() >= chunk_size_) {
+        return Flush();
+    }
+    return Success;
+}
+
+Result<SuccessType> SemanticTokensStream::Flush() {
+    if (pending_.empty()) {
+        return Success;
+    }
+    SemanticTokensPartialResult chunk;
+    chunk.data = std::move(pending_);
+    pending_.clear();
+    pending_.reserve(std::min(chunk_size_, kMaxReservedInts));
+    return on_chunk_(std::move(chunk));
+}
+
+Result<SuccessType> ValidateSemanticTokens(const SemanticTokensLegend& legend,
+                                           const std::vector<Uinteger>& data) {
+    if (data.size() % kIntsPerToken != 0) {
+        return Failure{"semantic token data length is not a multiple of " +
+                       std::to_string(kIntsPerToken)};
+    }
+    for (size_t i = 0; i < data.size(); i += kIntsPerToken) {
+        Uinteger type = data[i + 3];
+        Uinteger bits = data[i + 4];
+        if (type >= legend.token_types.size()) {
+            return Failure{"semantic token type index " + std::to_string(type) +
+                           " is out of range of the legend"};
+        }
+        for (size_t bit = legend.token_modifiers.size(); bit < sizeof(Uinteger) * 8; bit++) {
+            if (bits & (Uinteger{1} << bit)) {
+                return Failure{"semantic token modifier bit " + std::to_string(bit) +
+                               " is out of range of the legend"};
+            }
+        }
+    }
+    return Success;
+}
+
+Result<std::vector<SemanticToken>> DecodeSemanticTokens(const SemanticTokensLegend& legend,
+                                                        const std::vector<Uinteger>& data) {
+    if (auto res = ValidateSemanticTokens(legend, data); res != Success) {
+        return res.Failure();
+    }
+
+    std::vector<SemanticToken> tokens;
+    tokens.reserve(data.size() / kIntsPerToken);
+
+    Uinteger line = 0;
+    Uinteger character = 0;
+    for (size_t i = 0; i < data.size(); i += kIntsPerToken) {
+        Uinteger delta_line = data[i + 0];
+        Uinteger delta_start = data[i + 1];
+        Uinteger length = data[i + 2];
+        Uinteger type = data[i + 3];
+        Uinteger bits = data[i + 4];
+
+        line += delta_line;
+        character = delta_line == 0 ? character + delta_start : delta_start;
+
+        SemanticToken token{line, character, length, legend.token_types[type], {}};
+        for (size_t bit = 0; bits != 0; bit++, bits >>= 1) {
+            if ((bits & 1) == 0) {
+                continue;
+            }
+            token.modifiers.push_back(legend.token_modifiers[bit]);
+        }
+        tokens.push_back(std::move(token));
+    }
+    return tokens;
+}
+
+Uinteger SemanticTokensLegendBuilder::AddType(std::string_view type) {
+    return IndexOf(legend_.token_types, type);
+}
+
+Uinteger SemanticTokensLegendBuilder::AddType(SemanticTokenTypes type) {
+    return AddType(ToString(type));
+}
+
+Result<Uinteger> SemanticTokensLegendBuilder::AddModifier(std::string_view modifier) {
+    auto& modifiers = legend_.token_modifiers;
+    if (modifiers.size() >= kMaxModifiers &&
+        std::find(modifiers.begin(), modifiers.end(), modifier) == modifiers.end()) {
+        return Failure{"cannot add semantic token modifier '" + std::string(modifier) +
+                       "': the legend already holds " + std::to_string(kMaxModifiers) +
+                       " modifiers"};
+    }
+    return IndexOf(modifiers, modifier);
+}
+
+Result<Uinteger> SemanticTokensLegendBuilder::AddModifier(SemanticTokenModifiers modifier) {
+    return AddModifier(ToString(modifier));
+}
+
+SemanticTokensLegend NegotiateSemanticTokensLegend(const SemanticTokensLegend& server,
+                                                   const SemanticTokensClientCapabilities& client) {
+    auto intersect = [](const std::vector<String>& list, const std::vector<String>& supported) {
+        std::unordered_set<std::string_view> set(supported.begin(), supported.end());
+        std::vector<String> out;
+        for (auto& item : list) {
+            if (set.count(item)) {
+                out.push_back(item);
+            }
+        }
+        return out;
+    };
+
+    SemanticTokensLegend out;
+    out.token_types = intersect(server.token_types, client.token_types);
+    out.token_modifiers = intersect(server.token_modifiers, client.token_modifiers);
+    return out;
+}
+
+Result<std::vector<SemanticToken>> FilterSemanticTokens(const SemanticTokensLegend& legend,
+                                                        std::vector<SemanticToken> tokens,
+                                                        const Optional<String>& fallback_type) {
+    std::unordered_set<std::string_view> types(legend.token_types.begin(),
+                                               legend.token_types.end());
+    std::unordered_set<std::string_view> modifiers(legend.token_modifiers.begin(),
+                                                   legend.token_modifiers.end());
+    if (fallback_type && !types.count(*fallback_type)) {
+        return Failure{"fallback semantic token type '" + *fallback_type +
+                       "' is not in the legend"};
+    }
+
+    std::vector<SemanticToken> out;
+    out.reserve(tokens.size());
+    for (auto& token : tokens) {
+        if (!types.count(token.type)) {
+            if (!fallback_type) {
+                continue;
+            }
+            token.type = *fallback_type;
+        }
+        std::erase_if(token.modifiers, [&](const String& m) { return !modifiers.count(m); });
+        out.push_back(std::move(token));
+    }
+    return out;
+}
+
+}  // namespace langsvr::lsp
@@ -0,0 +1,410 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/semantic_tokens.h"
+
+#include <string>
+#include <utility>
+#include <vector>
+
+#include "gmock/gmock.h"
+#include "langsvr/session.h"
+
+namespace langsvr::lsp {
+namespace {
+
+SemanticTokensLegend Legend() {
+    SemanticTokensLegend legend;
+    legend.token_types = {"namespace", "type", "function", "variable"};
+    legend.token_modifiers = {"declaration", "readonly", "static"};
+    return legend;
+}
+
+TEST(SemanticTokensTest, EncodeEmpty) {
+    auto data = EncodeSemanticTokens(Legend(), {});
+    ASSERT_EQ(data, Success);
+    EXPECT_TRUE(data->empty());
+}
+
+TEST(SemanticTokensTest, Encode) {
+    std::vector<SemanticToken> tokens{
+        {2, 5, 3, "type", {}},
+        {2, 10, 4, "function", {"declaration"}},
+        {5, 2, 7, "variable", {"readonly", "static"}},
+    };
+    auto data = EncodeSemanticTokens(Legend(), tokens);
+    ASSERT_EQ(data, Success);
+    std::vector<Uinteger> expect{
+        2, 5, 3, 1, 0,  //
+        0, 5, 4, 2, 1,  //
+        3, 2, 7, 3, 6,  //
+    };
+    EXPECT_EQ(data.Get(), expect);
+}
+
+TEST(SemanticTokensTest, EncodeUnknownType) {
+    auto data = EncodeSemanticTokens(Legend(), {{0, 0, 1, "macro", {}}});
+    ASSERT_NE(data, Success);
+    EXPECT_EQ(data.Failure().reason, "unknown semantic token type 'macro'");
+}
+
+TEST(SemanticTokensTest, EncodeUnknownModifier) {
+    auto data = EncodeSemanticTokens(Legend(), {{0, 0, 1, "type", {"async"}}});
+    ASSERT_NE(data, Success);
+    EXPECT_EQ(data.Failure().reason, "unknown semantic token modifier 'async'");
+}
+
+TEST(SemanticTokensTest, EncodeUnsorted) {
+    std::vector<SemanticToken> tokens{
+        {1, 8, 3, "type", {}},
+        {1, 4, 3, "type", {}},
+    };
+    auto data = EncodeSemanticTokens(Legend(), tokens);
+    ASSERT_NE(data, Success);
+    EXPECT_EQ(data.Failure().reason,
+              "semantic token {1:4 len: 3 type: type modifiers: []} is not sorted by position");
+}
+
+TEST(SemanticTokensTest, StreamChunks) {
+    std::vector<SemanticToken> tokens;
+    for (Uinteger i = 0; i < 7; i++) {
+        tokens.push_back({i / 2, (i % 2) * 4, 2, "variable", {}});
+    }
+
+    std::vector<std::vector<Uinteger>> chunks;
+    // A chunk size of 12 integers holds 2 tokens
+    SemanticTokensStream stream(Legend(), 12, [&](SemanticTokensPartialResult&& chunk) {
+        chunks.push_back(std::move(chunk.data));
+        return Success;
+    });
+    for (auto& token : tokens) {
+        ASSERT_EQ(stream.Add(token), Success);
+    }
+    ASSERT_EQ(stream.Flush(), Success);
+    EXPECT_EQ(stream.Flush(), Success);  // Nothing pending
+
+    ASSERT_EQ(chunks.size(), 4u);
+    std::vector<Uinteger> concatenated;
+    for (size_t i = 0; i < chunks.size(); i++) {
+        EXPECT_EQ(chunks[i].size(), i < 3 ? 10u : 5u);
+        concatenated.insert(concatenated.end(), chunks[i].begin(), chunks[i].end());
+    }
+    auto data = EncodeSemanticTokens(Legend(), tokens);
+    ASSERT_EQ(data, Success);
+    EXPECT_EQ(concatenated, data.Get());
+}
+
+TEST(SemanticTokensTest, StreamErrors) {
+    auto fail = [&](SemanticTokensPartialResult&&) -> Result<SuccessType> {
+        return Failure{"send failed"};
+    };
+    SemanticTokensStream stream(Legend(), 0, fail);
+    EXPECT_NE(stream.Add({0, 0, 1, "macro", {}}), Success);
+    auto res = stream.Add({0, 0, 1, "type", {}});
+    ASSERT_NE(res, Success);
+    EXPECT_EQ(res.Failure().reason, "send failed");
+}
+
+TEST(SemanticTokensTest, StreamPartialResults) {
+    ServerSession server;
+    ClientSession client;
+    server.SetSender([&](std::string_view msg) { return client.Receive(msg); });
+
+    std::vector<Uinteger> received;
+    client.Register([&](const ProgressNotification& notification) -> Result<SuccessType> {
+        EXPECT_EQ(*notification.token.Get<String>(), "partial");
+        auto value = DecodeProgressValue<SemanticTokensPartialResult>(notification);
+        if (value != Success) {
+            return value.Failure();
+        }
+        received.insert(received.end(), value->data.begin(), value->data.end());
+        return Success;
+    });
+
+    std::vector<SemanticToken> tokens{{0, 0, 1, "type", {}}, {3, 1, 2, "function", {"static"}}};
+    auto stream = StreamSemanticTokens(server, ProgressToken{String{"partial"}}, Legend(), 5);
+    for (auto& token : tokens) {
+        ASSERT_EQ(stream.Add(token), Success);
+    }
+    ASSERT_EQ(stream.Flush(), Success);
+    EXPECT_EQ(received, EncodeSemanticTokens(Legend(), tokens).Get());
+}
+
+TEST(SemanticTokensTest, StreamRequestPartialResultToken) {
+    static constexpr std::string_view kSemanticTokensMsg =
+        R"({"jsonrpc":"2.0","id":1,"method":"textDocument/semanticTokens/full","params":{"textDocument":{"uri":"file:///a.cc"},"partialResultToken":"tokens"}})";
+
+    ServerSession server;
+    ClientSession client;
+    std::vector<std::string> responses;
+    server.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        if (msg.find("$/progress") != std::string_view::npos) {
+            return client.Receive(msg);
+        }
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    std::vector<Uinteger> received;
+    client.Register([&](const ProgressNotification& notification) -> Result<SuccessType> {
+        EXPECT_EQ(*notification.token.Get<String>(), "tokens");
+        auto value = DecodeProgressValue<SemanticTokensPartialResult>(notification);
+        if (value != Success) {
+            return value.Failure();
+        }
+        received.insert(received.end(), value->data.begin(), value->data.end());
+        return Success;
+    });
+
+    std::vector<SemanticToken> tokens{{0, 0, 1, "type", {}}, {3, 1, 2, "function", {"static"}}};
+    server.Register([&](const TextDocumentSemanticTokensFullRequest& request)
+                        -> Result<TextDocumentSemanticTokensFullRequest::Result> {
+        if (!request.partial_result_token) {
+            return Failure{"no partial result token"};
+        }
+        auto stream = StreamSemanticTokens(server, *request.partial_result_token, Legend(), 5);
+        for (auto& token : tokens) {
+            if (auto res = stream.Add(token); res != Success) {
+                return res.Failure();
+            }
+        }
+        if (auto res = stream.Flush(); res != Success) {
+            return res.Failure();
+        }
+        return TextDocumentSemanticTokensFullRequest::Result{SemanticTokens{}};
+    });
+
+    EXPECT_EQ(server.Receive(kSemanticTokensMsg), Success);
+    EXPECT_EQ(received, EncodeSemanticTokens(Legend(), tokens).Get());
+    EXPECT_THAT(responses, testing::ElementsAre(R"({"id":1,"result":{"data":[]}})"));
+}
+
+TEST(SemanticTokensTest, Decode) {
+    std::vector<Uinteger> data{
+        2, 5, 3, 1, 0,  //
+        0, 5, 4, 2, 1,  //
+        3, 2, 7, 3, 6,  //
+    };
+    auto tokens = DecodeSemanticTokens(Legend(), data);
+    ASSERT_EQ(tokens, Success);
+    std::vector<SemanticToken> expect{
+        {2, 5, 3, "type", {}},
+        {2, 10, 4, "function", {"declaration"}},
+        {5, 2, 7, "variable", {"readonly", "static"}},
+    };
+    EXPECT_EQ(tokens.Get(), expect);
+}
+
+TEST(SemanticTokensTest, DecodeBadLength) {
+    auto tokens = DecodeSemanticTokens(Legend(), {0, 0, 1, 1});
+    ASSERT_NE(tokens, Success);
+    EXPECT_EQ(tokens.Failure().reason, "semantic token data length is not a multiple of 5");
+}
+
+TEST(SemanticTokensTest, DecodeTypeOutOfRange) {
+    auto tokens = DecodeSemanticTokens(Legend(), {0, 0, 1, 4, 0});
+    ASSERT_NE(tokens, Success);
+    EXPECT_EQ(tokens.Failure().reason, "semantic token type index 4 is out of range of the legend");
+}
+
+TEST(SemanticTokensTest, DecodeModifierOutOfRange) {
+    auto tokens = DecodeSemanticTokens(Legend(), {0, 0, 1, 0, 8});
+    ASSERT_NE(tokens, Success);
+    EXPECT_EQ(tokens.Failure().reason,
+              "semantic token modifier bit 3 is out of range of the legend");
+}
+
+TEST(SemanticTokensTest, Validate) {
+    EXPECT_EQ(ValidateSemanticTokens(Legend(), {}), Success);
+    EXPECT_EQ(ValidateSemanticTokens(Legend(), {0, 0, 1, 3, 7, 1, 2, 3, 0, 0}), Success);
+    EXPECT_NE(ValidateSemanticTokens(Legend(), {0, 0, 1, 3}), Success);
+    EXPECT_NE(ValidateSemanticTokens(Legend(), {0, 0, 1, 3, 7, 1, 2, 3, 4, 0}), Success);
+    EXPECT_NE(ValidateSemanticTokens(Legend(), {0, 0, 1, 3, 7, 1, 2, 3, 0, 0x80000000}), Success);
+    EXPECT_NE(ValidateSemanticTokens(Legend(), {0, 0, 1, 3, Uinteger{1} << 40}), Success);
+}
+
+TEST(SemanticTokensTest, LegendBuilder) {
+    SemanticTokensLegendBuilder builder;
+    EXPECT_EQ(builder.AddType(SemanticTokenTypes::kNamespace), 0u);
+    EXPECT_EQ(builder.AddType("custom"), 1u);
+    EXPECT_EQ(builder.AddType("namespace"), 0u);
+    EXPECT_EQ(builder.AddType(SemanticTokenTypes::kEnumMember), 2u);
+
+    auto declaration = builder.AddModifier(SemanticTokenModifiers::kDeclaration);
+    ASSERT_EQ(declaration, Success);
+    EXPECT_EQ(declaration.Get(), 0u);
+    auto readonly = builder.AddModifier("readonly");
+    ASSERT_EQ(readonly, Success);
+    EXPECT_EQ(readonly.Get(), 1u);
+    auto again = builder.AddModifier(SemanticTokenModifiers::kReadonly);
+    ASSERT_EQ(again, Success);
+    EXPECT_EQ(again.Get(), 1u);
+
+    EXPECT_THAT(builder.Legend().token_types,
+                testing::ElementsAre("namespace", "custom", "enumMember"));
+    EXPECT_THAT(builder.Legend().token_modifiers, testing::ElementsAre("declaration", "readonly"));
+}
+
+TEST(SemanticTokensTest, LegendBuilderTooManyModifiers) {
+    SemanticTokensLegendBuilder builder;
+    for (int i = 0; i < 32; i++) {
+        auto modifier = builder.AddModifier("m" + std::to_string(i));
+        ASSERT_EQ(modifier, Success);
+        EXPECT_EQ(modifier.Get(), static_cast<Uinteger>(i));
+    }
+    EXPECT_EQ(builder.AddModifier("m31"), Success);
+    auto overflow = builder.AddModifier("m32");
+    ASSERT_NE(overflow, Success);
+    EXPECT_EQ(overflow.Failure().reason,
+              "cannot add semantic token modifier 'm32': the legend already holds 32 modifiers");
+}
+
+TEST(SemanticTokensTest, RoundTrip) {
+    std::vector<SemanticToken> tokens{
+        {0, 0, 9, "namespace", {"declaration"}},
+        {0, 10, 3, "type", {"declaration", "static"}},
+        {0, 14, 1, "variable", {}},
+        {1, 4, 8, "function", {"static"}},
+        {1, 13, 1, "variable", {"readonly"}},
+        {7, 0, 2, "type", {}},
+        {7, 2, 2, "type", {}},
+    };
+    auto data = EncodeSemanticTokens(Legend(), tokens);
+    ASSERT_EQ(data, Success);
+    auto decoded = DecodeSemanticTokens(Legend(), data.Get());
+    ASSERT_EQ(decoded, Success);
+    EXPECT_EQ(decoded.Get(), tokens);
+}
+
+TEST(SemanticTokensTest, NegotiateLegend) {
+    SemanticTokensClientCapabilities client;
+    client.token_types = {"variable", "type", "keyword"};
+    client.token_modifiers = {"static", "declaration", "async"};
+
+    auto legend = NegotiateSemanticTokensLegend(Legend(), client);
+    EXPECT_EQ(legend.token_types, (std::vector<String>{"type", "variable"}));
+    EXPECT_EQ(legend.token_modifiers, (std::vector<String>{"declaration", "static"}));
+}
+
+TEST(SemanticTokensTest, NegotiateLegendRemapsIndices) {
+    SemanticTokensClientCapabilities client;
+    client.token_types = {"function", "variable"};
+    client.token_modifiers = {"static"};
+    auto legend = NegotiateSemanticTokensLegend(Legend(), client);
+
+    std::vector<SemanticToken> tokens{
+        {0, 0, 3, "function", {}},
+        {0, 4, 1, "variable", {"static"}},
+    };
+    auto data = EncodeSemanticTokens(legend, tokens);
+    ASSERT_EQ(data, Success);
+    std::vector<Uinteger> expect{
+        0, 0, 3, 0, 0,  //
+        0, 4, 1, 1, 1,  //
+    };
+    EXPECT_EQ(data.Get(), expect);
+}
+
+TEST(SemanticTokensTest, FilterDrop) {
+    SemanticTokensLegend legend;
+    legend.token_types = {"type", "variable"};
+    legend.token_modifiers = {"static"};
+
+    std::vector<SemanticToken> tokens{
+        {0, 0, 9, "namespace", {"declaration"}},
+        {0, 10, 3, "type", {"declaration", "static"}},
+        {1, 4, 8, "function", {"static"}},
+        {1, 13, 1, "variable", {"readonly"}},
+    };
+    auto filtered = FilterSemanticTokens(legend, tokens);
+    ASSERT_EQ(filtered, Success);
+    std::vector<SemanticToken> expect{
+        {0, 10, 3, "type", {"static"}},
+        {1, 13, 1, "variable", {}},
+    };
+    EXPECT_EQ(filtered.Get(), expect);
+}
+
+TEST(SemanticTokensTest, FilterFallback) {
+    SemanticTokensLegend legend;
+    legend.token_types = {"type", "variable"};
+    legend.token_modifiers = {"static"};
+
+    std::vector<SemanticToken> tokens{
+        {0, 0, 9, "namespace", {"declaration"}},
+        {0, 10, 3, "type", {"declaration", "static"}},
+        {1, 4, 8, "function", {"static"}},
+    };
+    auto filtered = FilterSemanticTokens(legend, tokens, String{"variable"});
+    ASSERT_EQ(filtered, Success);
+    std::vector<SemanticToken> expect{
+        {0, 0, 9, "variable", {}},
+        {0, 10, 3, "type", {"static"}},
+        {1, 4, 8, "variable", {"static"}},
+    };
+    EXPECT_EQ(filtered.Get(), expect);
+}
+
+TEST(SemanticTokensTest, FilterFallbackNotInLegend) {
+    SemanticTokensLegend legend;
+    legend.token_types = {"type"};
+
+    auto filtered = FilterSemanticTokens(legend, {}, String{"variable"});
+    ASSERT_NE(filtered, Success);
+    EXPECT_EQ(filtered.Failure().reason,
+              "fallback semantic token type 'variable' is not in the legend");
+}
+
+TEST(SemanticTokensTest, FilteredTokensEncodeWithValidIndices) {
+    SemanticTokensClientCapabilities client;
+    client.token_types = {"type", "variable"};
+    client.token_modifiers = {"readonly"};
+    auto legend = NegotiateSemanticTokensLegend(Legend(), client);
+
+    std::vector<SemanticToken> tokens{
+        {0, 0, 9, "namespace", {"declaration"}},
+        {0, 10, 3, "type", {"declaration", "static"}},
+        {1, 4, 8, "function", {"static"}},
+        {1, 13, 1, "variable", {"readonly"}},
+    };
+    for (auto fallback : {Optional<String>{}, Optional<String>{"variable"}}) {
+        auto filtered = FilterSemanticTokens(legend, tokens, fallback);
+        ASSERT_EQ(filtered, Success);
+        auto data = EncodeSemanticTokens(legend, filtered.Get());
+        ASSERT_EQ(data, Success);
+        for (size_t i = 0; i < data->size(); i += 5) {
+            EXPECT_LT(data.Get()[i + 3], legend.token_types.size());
+            EXPECT_LT(data.Get()[i + 4], Uinteger{1} << legend.token_modifiers.size());
+        }
+        auto decoded = DecodeSemanticTokens(legend, data.Get());
+        ASSERT_EQ(decoded, Success);
+        EXPECT_EQ(decoded.Get(), filtered.Get());
+    }
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -27,6 +27,9 @@
 
 #include "langsvr/session.h"
 #include "langsvr/lsp/lsp.h"
+#include "langsvr/lsp/response_error.h"
+
+#include <stdexcept>
 
 #include "gmock/gmock.h"
 
@@ -75,5 +78,394 @@ TEST(Session, MsgInit) {
                                R"({"id":10,"result":{"capabilities":{"hoverProvider":true}}})"));
 }
 
+static constexpr std::string_view kPublishDiagnosticsMsg =
+    R"({"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.cc","diagnostics":[]}})";
+
+static constexpr std::string_view kHoverMsg =
+    R"({"jsonrpc":"2.0","id":1,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///a.cc"},"position":{"line":1,"character":2}}})";
+
+static constexpr std::string_view kCancelRequestMsg =
+    R"({"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":1}})";
+
+TEST(Session, ServerRejectsServerToClientNotification) {
+    Session session;
+
+    bool handler_called = false;
+    session.Register([&](const lsp::TextDocumentPublishDiagnosticsNotification&) {
+        handler_called = true;
+        return Success;
+    });
+
+    std::vector<std::string> warnings;
+    session.SetWarningHandler([&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+    auto res = session.Receive(kPublishDiagnosticsMsg);
+    ASSERT_NE(res, Success);
+    EXPECT_EQ(res.Failure().reason,
+              "'textDocument/publishDiagnostics' is not sent from the client to the server");
+    EXPECT_FALSE(handler_called);
+    EXPECT_THAT(warnings,
+                testing::ElementsAre(
+                    "'textDocument/publishDiagnostics' is not sent from the client to the server"));
+}
+
+TEST(Session, ServerPermissiveAcceptsServerToClientNotification) {
+    Session session;
+    session.SetPermissiveMessageDirection(true);
+
+    bool handler_called = false;
+    session.Register([&](const lsp::TextDocumentPublishDiagnosticsNotification&) {
+        handler_called = true;
+        return Success;
+    });
+
+    std::vector<std::string> warnings;
+    session.SetWarningHandler([&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+    EXPECT_EQ(session.Receive(kPublishDiagnosticsMsg), Success);
+    EXPECT_TRUE(handler_called);
+    EXPECT_THAT(warnings,
+                testing::ElementsAre(
+                    "'textDocument/publishDiagnostics' is not sent from the client to the server"));
+}
+
+TEST(Session, ClientRejectsClientToServerRequest) {
+    Session session;
+    session.SetRole(Session::Role::kClient);
+
+    bool handler_called = false;
+    session.Register(
+        [&](const lsp::TextDocumentHoverRequest&) -> lsp::TextDocumentHoverRequest::Result {
+            handler_called = true;
+            return {lsp::Null{}};
+        });
+
+    std::vector<std::string> responses;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    std::vector<std::string> warnings;
+    session.SetWarningHandler([&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+    auto res = session.Receive(kHoverMsg);
+    ASSERT_NE(res, Success);
+    EXPECT_EQ(res.Failure().reason,
+              "'textDocument/hover' is not sent from the server to the client");
+    EXPECT_FALSE(handler_called);
+    EXPECT_THAT(
+        responses,
+        testing::ElementsAre(
+            R"({"error":{"code":-32601,"message":"'textDocument/hover' is not sent from the server to the client"},"id":1})"));
+    EXPECT_THAT(warnings, testing::ElementsAre(
+                              "'textDocument/hover' is not sent from the server to the client"));
+}
+
+TEST(Session, ClientPermissiveAcceptsClientToServerRequest) {
+    Session session;
+    session.SetRole(Session::Role::kClient);
+    session.SetPermissiveMessageDirection(true);
+
+    bool handler_called = false;
+    session.Register(
+        [&](const lsp::TextDocumentHoverRequest&) -> lsp::TextDocumentHoverRequest::Result {
+            handler_called = true;
+            return {lsp::Null{}};
+        });
+
+    std::vector<std::string> responses;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    std::vector<std::string> warnings;
+    session.SetWarningHandler([&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+    EXPECT_EQ(session.Receive(kHoverMsg), Success);
+    EXPECT_TRUE(handler_called);
+    EXPECT_THAT(responses, testing::ElementsAre(R"({"id":1,"result":null})"));
+    EXPECT_THAT(warnings, testing::ElementsAre(
+                              "'textDocument/hover' is not sent from the server to the client"));
+}
+
+TEST(Session, BidirectionalNotification) {
+    for (auto role : {Session::Role::kServer, Session::Role::kClient}) {
+        Session session;
+        session.SetRole(role);
+
+        bool handler_called = false;
+        session.Register([&](const lsp::CancelRequestNotification&) {
+            handler_called = true;
+            return Success;
+        });
+
+        std::vector<std::string> warnings;
+        session.SetWarningHandler(
+            [&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+        EXPECT_EQ(session.Receive(kCancelRequestMsg), Success);
+        EXPECT_TRUE(handler_called);
+        EXPECT_TRUE(warnings.empty());
+    }
+}
+
+template <typename SESSION, typename MESSAGE>
+concept CanRegisterNotification = requires(SESSION& session) {
+    session.Register([](const MESSAGE&) { return Success; });
+};
+
+template <typename SESSION, typename MESSAGE>
+concept CanRegisterRequest = requires(SESSION& session) {
+    session.Register([](const MESSAGE&) -> Result<typename MESSAGE::Result> {
+        return typename MESSAGE::Result{};
+    });
+};
+
+template <typename SESSION, typename MESSAGE>
+concept CanSend = requires(SESSION& session, MESSAGE message) { session.Send(message); };
+
+// Client -> server
+static_assert(CanRegisterRequest<ServerSession, lsp::TextDocumentHoverRequest>);
+static_assert(!CanRegisterRequest<ClientSession, lsp::TextDocumentHoverRequest>);
+static_assert(!CanSend<ServerSession, lsp::TextDocumentHoverRequest>);
+static_assert(CanSend<ClientSession, lsp::TextDocumentHoverRequest>);
+static_assert(CanRegisterNotification<ServerSession, lsp::TextDocumentDidOpenNotification>);
+static_assert(!CanRegisterNotification<ClientSession, lsp::TextDocumentDidOpenNotification>);
+
+// Server -> client
+static_assert(!CanRegisterRequest<ServerSession, lsp::WindowWorkDoneProgressCreateRequest>);
+static_assert(CanRegisterRequest<ClientSession, lsp::WindowWorkDoneProgressCreateRequest>);
+static_assert(CanSend<ServerSession, lsp::TextDocumentPublishDiagnosticsNotification>);
+static_assert(!CanSend<ClientSession, lsp::TextDocumentPublishDiagnosticsNotification>);
+static_assert(
+    !CanRegisterNotification<ServerSession, lsp::TextDocumentPublishDiagnosticsNotification>);
+static_assert(
+    CanRegisterNotification<ClientSession, lsp::TextDocumentPublishDiagnosticsNotification>);
+
+// Both
+static_assert(CanRegisterNotification<ServerSession, lsp::CancelRequestNotification>);
+static_assert(CanRegisterNotification<ClientSession, lsp::CancelRequestNotification>);
+static_assert(CanSend<ServerSession, lsp::CancelRequestNotification>);
+static_assert(CanSend<ClientSession, lsp::CancelRequestNotification>);
+
+TEST(Session, ServerSessionReceive) {
+    ServerSession session;
+
+    bool handler_called = false;
+    session.Register([&](const lsp::CancelRequestNotification&) {
+        handler_called = true;
+        return Success;
+    });
+
+    EXPECT_EQ(session.Receive(kCancelRequestMsg), Success);
+    EXPECT_TRUE(handler_called);
+
+    // Server-to-client messages cannot be registered, so are rejected as unhandled.
+    EXPECT_NE(session.Receive(kPublishDiagnosticsMsg), Success);
+}
+
+TEST(Session, ReceiveMalformedIncludesExcerpt) {
+    ServerSession session;
+
+    auto control = session.Receive("{\"method\":\x01\t\"caf\xc3\xa9\"\n");
+    ASSERT_NE(control, Success);
+    EXPECT_EQ(control.Failure().reason,
+              "failed to parse message: * Line 1, Column 11\n"
+              "  Syntax error: value, object or array expected.; "
+              "message: '{\"method\":\\x01\\t\"caf\\xc3\\xa9\"\\n'");
+
+    std::string large = "{\"method\": " + std::string(300, 'x') + "}";
+    auto truncated = session.Receive(large);
+    ASSERT_NE(truncated, Success);
+    EXPECT_EQ(truncated.Failure().reason,
+              "failed to parse message: * Line 1, Column 12\n"
+              "  Syntax error: value, object or array expected.; message: '" +
+                  large.substr(0, 256) + "... (312 bytes)'");
+}
+
+TEST(Session, ClientSessionSend) {
+    ClientSession session;
+
+    std::vector<std::string> sent;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        sent.push_back(std::string(msg));
+        return Success;
+    });
+
+    lsp::CancelRequestNotification cancel;
+    cancel.id = lsp::Integer{1};
+    EXPECT_EQ(session.Send(cancel), Success);
+    EXPECT_THAT(sent, testing::ElementsAre(R"({"method":"$/cancelRequest","params":{"id":1}})"));
+}
+
+TEST(Session, SendEncodesBaseMembers) {
+    ClientSession session;
+
+    std::vector<std::string> sent;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        sent.push_back(std::string(msg));
+        return Success;
+    });
+
+    // HoverParams has no members of its own. 'textDocument' and 'position' come from the base
+    // TextDocumentPositionParams.
+    lsp::TextDocumentHoverRequest hover;
+    hover.text_document.uri = "file:///a.cc";
+    hover.position.line = 1;
+    hover.position.character = 2;
+    EXPECT_EQ(session.Send(hover), Success);
+    EXPECT_THAT(
+        sent,
+        testing::ElementsAre(
+            R"({"method":"textDocument/hover","params":{"position":{"character":2,"line":1},"textDocument":{"uri":"file:///a.cc"}}})"));
+}
+
+#ifdef LANGSVR_LSP_3_18
+TEST(Session, WarnsAboutProposedFeatures) {
+    static_assert(lsp::TextDocumentInlineCompletionRequest::kProposed);
+    static_assert(!lsp::TextDocumentHoverRequest::kProposed);
+
+    for (bool allow : {false, true}) {
+        ServerSession session;
+        session.SetAllowProposedFeatures(allow);
+        session.Register([&](const lsp::TextDocumentInlineCompletionRequest&)
+                             -> Result<lsp::TextDocumentInlineCompletionRequest::Result> {
+            return {lsp::Null{}};
+        });
+        session.Register([&](const lsp::TextDocumentHoverRequest&)
+                             -> Result<lsp::TextDocumentHoverRequest::Result> {
+            return {lsp::Null{}};
+        });
+        session.Register([&](const lsp::CancelRequestNotification&) { return Success; });
+
+        std::vector<std::string> warnings;
+        session.SetWarningHandler(
+            [&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+        EXPECT_EQ(session.Receive(kCancelRequestMsg), Success);
+        EXPECT_EQ(session.Receive(kCancelRequestMsg), Success);
+        if (allow) {
+            EXPECT_THAT(warnings, testing::IsEmpty());
+        } else {
+            EXPECT_THAT(warnings, testing::ElementsAre("handler for method "
+                                                       "'textDocument/inlineCompletion' is a "
+                                                       "proposed LSP feature and may change"));
+        }
+    }
+}
+#endif  // LANGSVR_LSP_3_18
+
+TEST(Session, ExceptionsPropagateByDefault) {
+    ServerSession session;
+    session.Register([&](const lsp::CancelRequestNotification&) -> Result<SuccessType> {
+        throw std::runtime_error("oops");
+    });
+    EXPECT_THROW((void)session.Receive(kCancelRequestMsg), std::runtime_error);
+}
+
+TEST(Session, RecoverFromExceptions) {
+    static constexpr std::string_view kShutdownMsg =
+        R"({"jsonrpc":"2.0","id":5,"method":"shutdown"})";
+
+    ServerSession session;
+    session.SetRecoverFromExceptions(true);
+    session.Register([&](const lsp::ShutdownRequest&) -> Result<lsp::Null> {
+        throw std::runtime_error("database is locked");
+    });
+    session.Register([&](const lsp::CancelRequestNotification&) -> Result<SuccessType> {
+        throw 42;
+    });
+
+    std::vector<std::string> responses;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+    std::vector<std::string> warnings;
+    session.SetWarningHandler([&](std::string_view msg) { warnings.push_back(std::string(msg)); });
+
+    EXPECT_EQ(session.Receive(kShutdownMsg), Success);
+    ASSERT_EQ(responses.size(), 1u);
+    ASSERT_EQ(warnings.size(), 1u);
+
+    // The response is an InternalError that only holds the correlation id, which is also in the
+    // warning.
+    std::string prefix = R"({"error":{"code":-32603,)"
+                         R"("message":"internal error (correlation id: )";
+    ASSERT_TRUE(responses[0].starts_with(prefix));
+    std::string id = responses[0].substr(prefix.size(), 16);
+    EXPECT_EQ(responses[0], prefix + id + R"lit()"},"id":5})lit");
+    EXPECT_EQ(warnings[0], "handler for 'shutdown' threw an exception (correlation id: " + id +
+                               "): database is locked");
+
+    auto res = session.Receive(kCancelRequestMsg);
+    ASSERT_NE(res, Success);
+    EXPECT_THAT(res.Failure().reason, testing::HasSubstr("internal error (correlation id: "));
+    ASSERT_EQ(warnings.size(), 2u);
+    EXPECT_THAT(warnings[1], testing::HasSubstr("unknown exception"));
+}
+
+TEST(Session, RespondWithErrorData) {
+    static constexpr std::string_view kInitializeMsg =
+        R"({"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{},"processId":null,"rootUri":null}})";
+
+    ServerSession session;
+    bool fail = true;
+    using InitializeResult =
+        Result<lsp::InitializeResult, lsp::ResponseError<lsp::InitializeError>>;
+    session.Register([&](const lsp::InitializeRequest&) -> InitializeResult {
+        if (fail) {
+            lsp::ResponseError<lsp::InitializeError> error;
+            error.code = lsp::Integer{1};
+            error.message = "unknown protocol version";
+            error.data = lsp::InitializeError{/* retry */ true};
+            return error;
+        }
+        return lsp::InitializeResult{};
+    });
+
+    std::vector<std::string> responses;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    EXPECT_EQ(session.Receive(kInitializeMsg), Success);
+    fail = false;
+    EXPECT_EQ(session.Receive(kInitializeMsg), Success);
+    ASSERT_EQ(responses.size(), 2u);
+    EXPECT_EQ(
+        responses[0],
+        R"({"error":{"code":1,"data":{"retry":true},"message":"unknown protocol version"},"id":1})");
+    EXPECT_EQ(responses[1], R"({"id":1,"result":{"capabilities":{}}})");
+}
+
+TEST(Session, RespondWithErrorCode) {
+    static constexpr std::string_view kDiagnosticMsg =
+        R"({"jsonrpc":"2.0","id":2,"method":"textDocument/diagnostic","params":{"textDocument":{"uri":"file:///a.cc"}}})";
+
+    ServerSession session;
+    session.Register([&](const lsp::TextDocumentDiagnosticRequest&)
+                         -> Result<lsp::DocumentDiagnosticReport,
+                                   lsp::ResponseError<lsp::DiagnosticServerCancellationData>> {
+        lsp::ResponseError<lsp::DiagnosticServerCancellationData> error;
+        error.code = lsp::LSPErrorCodes::kServerCancelled;
+        error.message = "cancelled";
+        return error;
+    });
+
+    std::vector<std::string> responses;
+    session.SetSender([&](std::string_view msg) -> Result<SuccessType> {
+        responses.push_back(std::string(msg));
+        return Success;
+    });
+
+    EXPECT_EQ(session.Receive(kDiagnosticMsg), Success);
+    ASSERT_EQ(responses.size(), 1u);
+    EXPECT_EQ(responses[0], R"({"error":{"code":-32802,"message":"cancelled"},"id":2})");
+}
+
 }  // namespace
 }  // namespace langsvr
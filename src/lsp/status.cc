@@ -0,0 +1,60 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/status.h"
+
+#include <utility>
+#include <vector>
+
+#include "langsvr/json/builder.h"
+#include "langsvr/lsp/decode.h"
+#include "langsvr/lsp/encode.h"
+
+namespace langsvr::lsp {
+
+Result<SuccessType> Decode(const json::Value& v, StatusParams& out) {
+    for (auto [name, field] : {std::pair{"kind", &out.kind}, std::pair{"message", &out.message}}) {
+        auto member = v.Get(name);
+        if (member != Success) {
+            return member.Failure();
+        }
+        if (auto res = Decode(*member.Get(), *field); res != Success) {
+            return res.Failure();
+        }
+    }
+    return Success;
+}
+
+Result<const json::Value*> Encode(const StatusParams& in, json::Builder& b) {
+    std::vector members{
+        json::Builder::Member{"kind", b.String(in.kind)},
+        json::Builder::Member{"message", b.String(in.message)},
+    };
+    return b.Object(members);
+}
+
+}  // namespace langsvr::lsp
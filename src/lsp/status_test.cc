@@ -0,0 +1,69 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/status.h"
+
+#include <string>
+#include <vector>
+
+#include "gmock/gmock.h"
+#include "langsvr/session.h"
+
+namespace langsvr::lsp {
+namespace {
+
+TEST(StatusTest, SendStatus) {
+    ServerSession server;
+    ClientSession client;
+    std::vector<std::string> sent;
+    server.SetSender([&](std::string_view msg) {
+        sent.push_back(std::string(msg));
+        return client.Receive(msg);
+    });
+
+    std::vector<std::string> received;
+    client.Register([&](const StatusNotification& status) {
+        received.push_back(status.kind + ": " + status.message);
+        return Success;
+    });
+
+    EXPECT_EQ(SendStatus(server, "indexing", "3/10 files"), Success);
+    EXPECT_EQ(SendStatus(server, "idle", ""), Success);
+    EXPECT_THAT(received, testing::ElementsAre("indexing: 3/10 files", "idle: "));
+    ASSERT_EQ(sent.size(), 2u);
+    EXPECT_EQ(sent[0],
+              R"({"method":"$/status","params":{"kind":"indexing","message":"3/10 files"}})");
+}
+
+TEST(StatusTest, DecodeMissingMember) {
+    ClientSession client;
+    client.Register([&](const StatusNotification&) { return Success; });
+    EXPECT_NE(client.Receive(R"({"method":"$/status","params":{"kind":"idle"}})"), Success);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
@@ -0,0 +1,158 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/symbols.h"
+
+#include <algorithm>
+#include <type_traits>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// @returns the kinds to try in place of @p kind, nearest first
+std::vector<SymbolKind> Alternatives(SymbolKind kind) {
+    using K = SymbolKind;
+    switch (kind) {
+        case K::kModule:
+            return {K::kNamespace, K::kPackage};
+        case K::kNamespace:
+            return {K::kModule, K::kPackage};
+        case K::kPackage:
+            return {K::kNamespace, K::kModule};
+        case K::kMethod:
+            return {K::kFunction};
+        case K::kProperty:
+            return {K::kField, K::kVariable};
+        case K::kField:
+            return {K::kProperty, K::kVariable};
+        case K::kConstructor:
+            return {K::kMethod, K::kFunction};
+        case K::kEnum:
+            return {K::kClass};
+        case K::kInterface:
+            return {K::kClass};
+        case K::kFunction:
+            return {K::kMethod};
+        case K::kVariable:
+            return {K::kField, K::kProperty};
+        case K::kConstant:
+            return {K::kVariable};
+        case K::kString:
+        case K::kNumber:
+        case K::kBoolean:
+        case K::kArray:
+            return {K::kConstant, K::kVariable};
+        case K::kObject:
+            return {K::kClass, K::kVariable};
+        case K::kKey:
+            return {K::kProperty, K::kField, K::kString};
+        case K::kNull:
+            return {K::kConstant, K::kVariable};
+        case K::kEnumMember:
+            return {K::kConstant, K::kField, K::kEnum};
+        case K::kStruct:
+            return {K::kClass, K::kInterface};
+        case K::kEvent:
+            return {K::kField, K::kProperty, K::kFunction};
+        case K::kOperator:
+            return {K::kFunction, K::kMethod};
+        case K::kTypeParameter:
+            return {K::kClass, K::kInterface, K::kVariable};
+        default:
+            return {};
+    }
+}
+
+/// @returns the symbol kinds supported by a client with the capabilities @p capabilities
+const std::vector<SymbolKind>& SupportedKinds(
+    const Optional<ClientSymbolKindOptions>& capabilities) {
+    if (capabilities && capabilities->value_set) {
+        return *capabilities->value_set;
+    }
+    // Clients that do not declare a value set support the kinds from 'File' to 'Array'
+    static const std::vector<SymbolKind> kDefault = [] {
+        std::vector<SymbolKind> out;
+        for (auto kind = static_cast<int>(SymbolKind::kFile);
+             kind <= static_cast<int>(SymbolKind::kArray); kind++) {
+            out.push_back(static_cast<SymbolKind>(kind));
+        }
+        return out;
+    }();
+    return kDefault;
+}
+
+/// MapKinds maps the kind of each symbol of @p symbols using the kinds @p supported
+template <typename SYMBOL>
+void MapKinds(std::vector<SYMBOL>& symbols, const std::vector<SymbolKind>& supported) {
+    for (auto& symbol : symbols) {
+        symbol.kind = MapSymbolKind(symbol.kind, supported);
+        if constexpr (std::is_same_v<SYMBOL, DocumentSymbol>) {
+            if (symbol.children) {
+                MapKinds(*symbol.children, supported);
+            }
+        }
+    }
+}
+
+}  // namespace
+
+SymbolKind MapSymbolKind(SymbolKind kind, const std::vector<SymbolKind>& supported) {
+    auto is_supported = [&](SymbolKind k) {
+        return std::find(supported.begin(), supported.end(), k) != supported.end();
+    };
+    if (is_supported(kind)) {
+        return kind;
+    }
+    for (auto alternative : Alternatives(kind)) {
+        if (is_supported(alternative)) {
+            return alternative;
+        }
+    }
+    return kind;
+}
+
+std::vector<DocumentSymbol> MapSymbolKinds(std::vector<DocumentSymbol> symbols,
+                                           const Optional<ClientSymbolKindOptions>& capabilities) {
+    MapKinds(symbols, SupportedKinds(capabilities));
+    return symbols;
+}
+
+std::vector<SymbolInformation> MapSymbolKinds(
+    std::vector<SymbolInformation> symbols,
+    const Optional<ClientSymbolKindOptions>& capabilities) {
+    MapKinds(symbols, SupportedKinds(capabilities));
+    return symbols;
+}
+
+std::vector<WorkspaceSymbol> MapSymbolKinds(std::vector<WorkspaceSymbol> symbols,
+                                            const Optional<ClientSymbolKindOptions>& capabilities) {
+    MapKinds(symbols, SupportedKinds(capabilities));
+    return symbols;
+}
+
+}  // namespace langsvr::lsp
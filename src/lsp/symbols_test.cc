@@ -0,0 +1,121 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/symbols.h"
+
+#include <utility>
+#include <vector>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+using K = SymbolKind;
+
+TEST(SymbolsTest, MapAllKindsToInitialKinds) {
+    // The kinds from 'File' to 'Array' map to themselves. The kinds added later map to the nearest
+    // of these.
+    std::vector<std::pair<K, K>> expectations{
+        {K::kFile, K::kFile},
+        {K::kModule, K::kModule},
+        {K::kNamespace, K::kNamespace},
+        {K::kPackage, K::kPackage},
+        {K::kClass, K::kClass},
+        {K::kMethod, K::kMethod},
+        {K::kProperty, K::kProperty},
+        {K::kField, K::kField},
+        {K::kConstructor, K::kConstructor},
+        {K::kEnum, K::kEnum},
+        {K::kInterface, K::kInterface},
+        {K::kFunction, K::kFunction},
+        {K::kVariable, K::kVariable},
+        {K::kConstant, K::kConstant},
+        {K::kString, K::kString},
+        {K::kNumber, K::kNumber},
+        {K::kBoolean, K::kBoolean},
+        {K::kArray, K::kArray},
+        {K::kObject, K::kClass},
+        {K::kKey, K::kProperty},
+        {K::kNull, K::kConstant},
+        {K::kEnumMember, K::kConstant},
+        {K::kStruct, K::kClass},
+        {K::kEvent, K::kField},
+        {K::kOperator, K::kFunction},
+        {K::kTypeParameter, K::kClass},
+    };
+    ASSERT_EQ(expectations.size(), 26u);
+
+    std::vector<DocumentSymbol> symbols;
+    for (auto [kind, expect] : expectations) {
+        DocumentSymbol symbol;
+        symbol.kind = kind;
+        symbols.push_back(symbol);
+    }
+    auto mapped = MapSymbolKinds(symbols, Optional<ClientSymbolKindOptions>{});
+    ASSERT_EQ(mapped.size(), expectations.size());
+    for (size_t i = 0; i < mapped.size(); i++) {
+        EXPECT_EQ(mapped[i].kind, expectations[i].second) << "symbol kind " << i + 1;
+    }
+}
+
+TEST(SymbolsTest, MapKindFallbackOrder) {
+    EXPECT_EQ(MapSymbolKind(K::kStruct, {K::kStruct}), K::kStruct);
+    EXPECT_EQ(MapSymbolKind(K::kStruct, {K::kInterface}), K::kInterface);
+    EXPECT_EQ(MapSymbolKind(K::kConstructor, {K::kFunction, K::kMethod}), K::kMethod);
+    EXPECT_EQ(MapSymbolKind(K::kConstructor, {K::kFunction}), K::kFunction);
+    EXPECT_EQ(MapSymbolKind(K::kOperator, {K::kFile}), K::kOperator);
+}
+
+TEST(SymbolsTest, MapDocumentSymbolChildren) {
+    DocumentSymbol member;
+    member.kind = K::kEnumMember;
+    DocumentSymbol parent;
+    parent.kind = K::kEnum;
+    parent.children = std::vector{member};
+
+    ClientSymbolKindOptions capabilities;
+    capabilities.value_set = std::vector{K::kEnum, K::kField};
+    auto mapped = MapSymbolKinds(std::vector{parent}, capabilities);
+    ASSERT_EQ(mapped.size(), 1u);
+    EXPECT_EQ(mapped[0].kind, K::kEnum);
+    ASSERT_TRUE(mapped[0].children);
+    EXPECT_EQ((*mapped[0].children)[0].kind, K::kField);
+}
+
+TEST(SymbolsTest, MapSymbolInformation) {
+    SymbolInformation information;
+    information.kind = K::kTypeParameter;
+    WorkspaceSymbol workspace;
+    workspace.kind = K::kKey;
+
+    EXPECT_EQ(MapSymbolKinds(std::vector{information}, {})[0].kind, K::kClass);
+    EXPECT_EQ(MapSymbolKinds(std::vector{workspace}, {})[0].kind, K::kProperty);
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
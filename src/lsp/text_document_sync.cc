@@ -0,0 +1,501 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/text_document_sync.h"
+
+#include <algorithm>
+#include <optional>
+#include <sstream>
+#include <string>
+#include <utility>
+
+namespace langsvr::lsp {
+
+namespace {
+
+/// The maximum number of line insertions and deletions searched for by DiffLines(). Beyond this,
+/// the documents are treated as a single changed region.
+static constexpr int kMaxLineEdits = 1000;
+
+/// Lines is a document split into lines, along with the byte offset of each line.
+struct Lines {
+    /// The lines, including their line terminators
+    std::vector<std::string_view> lines;
+    /// The byte offset of the start of each line. Has one more entry than #lines, holding the
+    /// length of the document.
+    std::vector<size_t> offsets;
+};
+
+/// SplitLines splits @p text into lines. As in the protocol, '\n', '\r\n' and '\r' are all line
+/// terminators.
+Lines SplitLines(std::string_view text) {
+    Lines out;
+    size_t start = 0;
+    for (size_t i = 0; i < text.size(); i++) {
+        if (text[i] == '\r' && i + 1 < text.size() && text[i + 1] == '\n') {
+            i++;
+        }
+        if (text[i] == '\r' || text[i] == '\n') {
+            out.lines.push_back(text.substr(start, i + 1 - start));
+            out.offsets.push_back(start);
+            start = i + 1;
+        }
+    }
+    if (start < text.size()) {
+        out.lines.push_back(text.substr(start));
+        out.offsets.push_back(start);
+    }
+    out.offsets.push_back(text.size());
+    return out;
+}
+
+/// Hunk is a region of lines [a_begin, a_end) in the original document that is replaced by the
+/// lines [b_begin, b_end) of the revised document.
+struct Hunk {
+    size_t a_begin, a_end;
+    size_t b_begin, b_end;
+};
+
+/// DiffLines returns the hunks that transform the lines @p a into the lines @p b, using Myers'
+/// diff algorithm. Returns std::nullopt if more than kMaxLineEdits edits are required.
+std::optional<std::vector<Hunk>> DiffLines(const std::vector<std::string_view>& a,
+                                           const std::vector<std::string_view>& b) {
+    const int n = static_cast<int>(a.size());
+    const int m = static_cast<int>(b.size());
+    const int max = std::min(n + m, kMaxLineEdits);
+    const int offset = max + 1;
+
+    // v[offset + k] holds the furthest x reached on diagonal k. trace[d] holds v before step d.
+    std::vector<int> v(2 * static_cast<size_t>(max) + 3, 0);
+    std::vector<std::vector<int>> trace;
+
+    for (int d = 0; d <= max; d++) {
+        trace.push_back(v);
+        for (int k = -d; k <= d; k += 2) {
+            int x = (k == -d || (k != d && v[offset + k - 1] < v[offset + k + 1]))
+                        ? v[offset + k + 1]
+                        : v[offset + k - 1] + 1;
+            int y = x - k;
+            while (x < n && y < m && a[x] == b[y]) {
+                x++;
+                y++;
+            }
+            v[offset + k] = x;
+            if (x < n || y < m) {
+                continue;
+            }
+
+            // Reached the end. Walk back through the trace, collecting the matched lines.
+            std::vector<std::pair<int, int>> matches;
+            for (int e = d; e >= 0; e--) {
+                auto& prev = trace[e];
+                int kk = x - y;
+                bool down = kk == -e || (kk != e && prev[offset + kk - 1] < prev[offset + kk + 1]);
+                int prev_k = down ? kk + 1 : kk - 1;
+                int prev_x = e == 0 ? 0 : prev[offset + prev_k];
+                int prev_y = e == 0 ? 0 : prev_x - prev_k;
+                while (x > prev_x && y > prev_y) {
+                    x--;
+                    y--;
+                    matches.emplace_back(x, y);
+                }
+                x = prev_x;
+                y = prev_y;
+            }
+            std::reverse(matches.begin(), matches.end());
+
+            // Convert the gaps between the matched lines into hunks
+            std::vector<Hunk> hunks;
+            size_t a_next = 0, b_next = 0;
+            auto flush = [&](size_t a_end, size_t b_end) {
+                if (a_end > a_next || b_end > b_next) {
+                    hunks.push_back(Hunk{a_next, a_end, b_next, b_end});
+                }
+            };
+            for (auto [i, j] : matches) {
+                flush(static_cast<size_t>(i), static_cast<size_t>(j));
+                a_next = static_cast<size_t>(i) + 1;
+                b_next = static_cast<size_t>(j) + 1;
+            }
+            flush(a.size(), b.size());
+            return hunks;
+        }
+    }
+    return std::nullopt;
+}
+
+/// @returns true if @p offset is not between two bytes of a UTF-8 sequence, or between a '\r' and
+/// '\n' in @p text.
+bool IsBoundary(std::string_view text, size_t offset) {
+    if (offset == 0 || offset >= text.size()) {
+        return true;
+    }
+    if ((static_cast<unsigned char>(text[offset]) & 0xc0) == 0x80) {
+        return false;  // UTF-8 continuation byte
+    }
+    return !(text[offset - 1] == '\r' && text[offset] == '\n');
+}
+
+/// @returns the length of the UTF-8 string @p text in the units of @p encoding
+Uinteger EncodedLength(std::string_view text, PositionEncodingKind encoding) {
+    if (encoding == PositionEncodingKind::kUTF8) {
+        return text.size();
+    }
+    Uinteger len = 0;
+    for (char c : text) {
+        auto byte = static_cast<unsigned char>(c);
+        if ((byte & 0xc0) == 0x80) {
+            continue;  // UTF-8 continuation byte
+        }
+        // Code points encoded with 4 UTF-8 bytes are outside the BMP, and need a UTF-16 surrogate
+        // pair.
+        len += (encoding == PositionEncodingKind::kUTF16 && byte >= 0xf0) ? 2 : 1;
+    }
+    return len;
+}
+
+/// @returns the Position of the byte @p offset in the document @p text with the lines @p lines
+Position PositionOf(std::string_view text,
+                    const Lines& lines,
+                    size_t offset,
+                    PositionEncodingKind encoding) {
+    // The line is the last line that starts at or before offset
+    auto it = std::upper_bound(lines.offsets.begin(), lines.offsets.end() - 1, offset);
+    size_t line = static_cast<size_t>(it - lines.offsets.begin());
+    if (line > 0) {
+        line--;
+    }
+    if (line < lines.lines.size()) {
+        auto& str = lines.lines[line];
+        bool terminated = !str.empty() && (str.back() == '\n' || str.back() == '\r');
+        if (terminated && offset == lines.offsets[line] + str.size()) {
+            line++;  // offset is the start of the next (empty) line
+        }
+    }
+    size_t start = line < lines.offsets.size() ? lines.offsets[line] : text.size();
+    return Position{line, EncodedLength(text.substr(start, offset - start), encoding)};
+}
+
+/// @returns the byte offset of the Position @p pos in the document @p text with the lines @p lines.
+/// A position beyond the end of a line is clamped to the end of the line, excluding the line
+/// terminator, and a position beyond the last line is clamped to the end of the document.
+size_t OffsetOf(std::string_view text,
+                const Lines& lines,
+                const Position& pos,
+                PositionEncodingKind encoding) {
+    if (pos.line >= lines.lines.size()) {
+        return text.size();
+    }
+    std::string_view line = lines.lines[pos.line];
+    while (!line.empty() && (line.back() == '\n' || line.back() == '\r')) {
+        line.remove_suffix(1);
+    }
+    size_t offset = 0;
+    for (Uinteger units = 0; units < pos.character && offset < line.size();) {
+        auto byte = static_cast<unsigned char>(line[offset]);
+        units += (encoding == PositionEncodingKind::kUTF16 && byte >= 0xf0) ? 2 : 1;
+        offset++;
+        // Skip over the UTF-8 continuation bytes, unless positions are in bytes
+        while (encoding != PositionEncodingKind::kUTF8 && offset < line.size() &&
+               (static_cast<unsigned char>(line[offset]) & 0xc0) == 0x80) {
+            offset++;
+        }
+    }
+    return lines.offsets[pos.line] + offset;
+}
+
+/// @returns the code point that starts at the byte @p offset of the UTF-8 string @p text, and the
+/// number of bytes it occupies. A malformed UTF-8 sequence decodes as a single U+FFFD.
+std::pair<char32_t, size_t> DecodeCodePoint(std::string_view text, size_t offset) {
+    static constexpr std::pair<char32_t, size_t> kInvalid{0xfffd, 1};
+    auto lead = static_cast<unsigned char>(text[offset]);
+    size_t len = 0;
+    char32_t c = 0;
+    if (lead < 0x80) {
+        return {lead, 1};
+    } else if ((lead & 0xe0) == 0xc0) {
+        len = 2, c = lead & 0x1f;
+    } else if ((lead & 0xf0) == 0xe0) {
+        len = 3, c = lead & 0x0f;
+    } else if ((lead & 0xf8) == 0xf0) {
+        len = 4, c = lead & 0x07;
+    } else {
+        return kInvalid;
+    }
+    if (offset + len > text.size()) {
+        return kInvalid;
+    }
+    for (size_t i = 1; i < len; i++) {
+        auto byte = static_cast<unsigned char>(text[offset + i]);
+        if ((byte & 0xc0) != 0x80) {
+            return kInvalid;
+        }
+        c = (c << 6) | (byte & 0x3f);
+    }
+    return {c, len};
+}
+
+/// @returns the byte offset of the start of the code point before the byte @p offset of @p text
+size_t PrevCodePoint(std::string_view text, size_t offset) {
+    do {
+        offset--;
+    } while (offset > 0 && (static_cast<unsigned char>(text[offset]) & 0xc0) == 0x80);
+    return offset;
+}
+
+/// @returns @p position formatted as 'line:character'
+std::string ToString(const Position& position) {
+    return std::to_string(position.line) + ":" + std::to_string(position.character);
+}
+
+/// @returns @p range formatted as 'line:character-line:character'
+std::string ToString(const Range& range) {
+    std::stringstream out;
+    out << range.start.line << ":" << range.start.character << "-" << range.end.line << ":"
+        << range.end.character;
+    return out.str();
+}
+
+/// @returns the bytes [begin, end) of @p content, with the bytes [inner_begin, inner_end) replaced
+/// with @p text
+std::string Splice(std::string_view content,
+                   size_t begin,
+                   size_t end,
+                   size_t inner_begin,
+                   size_t inner_end,
+                   std::string_view text) {
+    std::string out{content.substr(begin, inner_begin - begin)};
+    out += text;
+    out += content.substr(inner_end, end - inner_end);
+    return out;
+}
+
+}  // namespace
+
+std::vector<TextDocumentContentChangePartial> OptimalTextDocumentSync(
+    std::string_view original,
+    std::string_view revised,
+    PositionEncodingKind encoding) {
+    if (original == revised) {
+        return {};
+    }
+
+    Lines a = SplitLines(original);
+    Lines b = SplitLines(revised);
+
+    auto hunks = DiffLines(a.lines, b.lines);
+    if (!hunks) {
+        hunks = std::vector{Hunk{0, a.lines.size(), 0, b.lines.size()}};
+    }
+
+    std::vector<TextDocumentContentChangePartial> changes;
+    changes.reserve(hunks->size());
+    for (auto hunk = hunks->rbegin(); hunk != hunks->rend(); hunk++) {
+        size_t a_begin = a.offsets[hunk->a_begin];
+        size_t a_end = a.offsets[hunk->a_end];
+        size_t b_begin = b.offsets[hunk->b_begin];
+        size_t b_end = b.offsets[hunk->b_end];
+
+        // Narrow the hunk to the bytes that differ
+        while (a_begin < a_end && b_begin < b_end && original[a_begin] == revised[b_begin]) {
+            a_begin++;
+            b_begin++;
+        }
+        while (a_end > a_begin && b_end > b_begin && original[a_end - 1] == revised[b_end - 1]) {
+            a_end--;
+            b_end--;
+        }
+        // Don't split UTF-8 sequences or '\r\n' line terminators. Invalid UTF-8 can hold
+        // continuation bytes with no lead byte, so stop at the start and end of the texts.
+        while (a_begin > 0 && b_begin > 0 &&
+               (!IsBoundary(original, a_begin) || !IsBoundary(revised, b_begin))) {
+            a_begin--;
+            b_begin--;
+        }
+        while (a_end < original.size() && b_end < revised.size() &&
+               (!IsBoundary(original, a_end) || !IsBoundary(revised, b_end))) {
+            a_end++;
+            b_end++;
+        }
+
+        TextDocumentContentChangePartial change;
+        change.range.start = PositionOf(original, a, a_begin, encoding);
+        change.range.end = PositionOf(original, a, a_end, encoding);
+        change.text = std::string(revised.substr(b_begin, b_end - b_begin));
+        changes.push_back(std::move(change));
+    }
+    return changes;
+}
+
+Result<std::string> SafeApplyTextEdits(std::string_view content,
+                                       const std::vector<TextEdit>& edits,
+                                       PositionEncodingKind encoding) {
+    /// Edit is a TextEdit with its range resolved to the byte offsets [begin, end) of content
+    struct Edit {
+        size_t begin, end;
+        const TextEdit* edit;
+    };
+
+    Lines lines = SplitLines(content);
+    std::vector<Edit> sorted;
+    sorted.reserve(edits.size());
+    for (auto& edit : edits) {
+        size_t begin = OffsetOf(content, lines, edit.range.start, encoding);
+        size_t end = OffsetOf(content, lines, edit.range.end, encoding);
+        if (begin > end) {
+            return Failure{"text edit range " + ToString(edit.range) + " ends before it starts"};
+        }
+        sorted.push_back(Edit{begin, end, &edit});
+    }
+    // Stable, so that inserts at the same position keep their order
+    std::stable_sort(sorted.begin(), sorted.end(), [](const Edit& a, const Edit& b) {
+        return std::pair(a.begin, a.end) < std::pair(b.begin, b.end);
+    });
+
+    // The previous edit replaced the bytes [prev_begin, offset) of content with the bytes of out
+    // from prev_out. Adjacent edits are merged by appending their text.
+    std::string out;
+    out.reserve(content.size());
+    size_t offset = 0;
+    size_t prev_begin = 0;
+    size_t prev_out = 0;
+    const TextEdit* prev = nullptr;
+    for (auto& edit : sorted) {
+        std::string_view text = edit.edit->new_text;
+        if (prev && edit.begin < offset) {
+            // The edit overlaps the previous edit. Merge them if one is nested in the other, and
+            // applying the inner one to content gives the same text as the outer one.
+            std::string_view prev_text = std::string_view(out).substr(prev_out);
+            if (edit.end <= offset) {
+                if (Splice(content, prev_begin, offset, edit.begin, edit.end, text) == prev_text) {
+                    continue;  // The previous edit holds this one, such as a duplicate
+                }
+            } else if (edit.begin == prev_begin) {
+                if (Splice(content, edit.begin, edit.end, prev_begin, offset, prev_text) == text) {
+                    out.resize(prev_out);  // This edit holds the previous one, so replaces it
+                    out.append(text);
+                    offset = edit.end;
+                    prev = edit.edit;
+                    continue;
+                }
+            }
+            return Failure{"text edit " + ToString(edit.edit->range) + " overlaps text edit " +
+                           ToString(prev->range)};
+        }
+        out.append(content.substr(offset, edit.begin - offset));
+        prev_begin = edit.begin;
+        prev_out = out.size();
+        out.append(text);
+        offset = edit.end;
+        prev = edit.edit;
+    }
+    out.append(content.substr(offset));
+    return out;
+}
+
+bool IsIdentifierCodePoint(char32_t c) {
+    if (c < 0x80) {
+        return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+               c == '_';
+    }
+    // The ranges of non-ASCII whitespace, punctuation and symbols
+    static constexpr std::pair<char32_t, char32_t> kNonIdentifier[] = {
+        {0x0080, 0x00a9},  // C1 controls, Latin-1 punctuation
+        {0x00ab, 0x00b4},  // Latin-1 punctuation and symbols
+        {0x00b6, 0x00b9},  // Latin-1 punctuation and symbols
+        {0x00bb, 0x00bf},  // Latin-1 punctuation and symbols
+        {0x00d7, 0x00d7},  // Multiplication sign
+        {0x00f7, 0x00f7},  // Division sign
+        {0x1680, 0x1680},  // Ogham space mark
+        {0x2000, 0x206f},  // General punctuation
+        {0x2190, 0x2bff},  // Arrows, mathematical operators, technical symbols, box drawing, ...
+        {0x2e00, 0x2e7f},  // Supplemental punctuation
+        {0x3000, 0x3003},  // CJK whitespace and punctuation
+        {0x3008, 0x3020},  // CJK brackets and symbols
+        {0xfd3e, 0xfd3f},  // Ornate parentheses
+        {0xfe10, 0xfe19},  // Vertical forms
+        {0xfe30, 0xfe4f},  // CJK compatibility forms
+        {0xfe50, 0xfe6f},  // Small form variants
+        {0xfeff, 0xfeff},  // Byte order mark
+        {0xff01, 0xff0f},  // Fullwidth punctuation
+        {0xff1a, 0xff20},  // Fullwidth punctuation
+        {0xff3b, 0xff3e},  // Fullwidth punctuation
+        {0xff40, 0xff40},  // Fullwidth grave accent
+        {0xff5b, 0xff65},  // Fullwidth and halfwidth punctuation
+        {0xfff0, 0xffff},  // Specials
+        {0x1f000, 0x1faff},  // Emoji and pictographic symbols
+    };
+    for (auto& [lo, hi] : kNonIdentifier) {
+        if (c >= lo && c <= hi) {
+            return false;
+        }
+    }
+    return true;
+}
+
+Result<Optional<Range>> WordRangeAt(std::string_view content,
+                                    const Position& position,
+                                    PositionEncodingKind encoding,
+                                    const WordPredicate& is_word) {
+    Lines lines = SplitLines(content);
+    size_t offset = OffsetOf(content, lines, position, encoding);
+    auto resolved = PositionOf(content, lines, offset, encoding);
+    if (resolved.line != position.line || resolved.character != position.character) {
+        return Failure{"position " + ToString(position) +
+                       " is not a valid position in the document"};
+    }
+
+    auto is_word_at = [&](size_t o) { return is_word(DecodeCodePoint(content, o).first); };
+
+    size_t start = 0;
+    if (offset < content.size() && is_word_at(offset)) {
+        start = offset;
+    } else if (offset > 0 && is_word_at(PrevCodePoint(content, offset))) {
+        start = PrevCodePoint(content, offset);
+    } else {
+        return Optional<Range>{};
+    }
+    while (start > 0) {
+        size_t prev = PrevCodePoint(content, start);
+        if (!is_word_at(prev)) {
+            break;
+        }
+        start = prev;
+    }
+    size_t end = start;
+    while (end < content.size()) {
+        auto [c, len] = DecodeCodePoint(content, end);
+        if (!is_word(c)) {
+            break;
+        }
+        end += len;
+    }
+    return Optional<Range>{Range{PositionOf(content, lines, start, encoding),
+                                 PositionOf(content, lines, end, encoding)}};
+}
+
+}  // namespace langsvr::lsp
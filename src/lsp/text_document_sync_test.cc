@@ -0,0 +1,359 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimev.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+#include "langsvr/lsp/text_document_sync.h"
+
+#include <random>
+#include <string>
+#include <string_view>
+
+#include "gmock/gmock.h"
+
+namespace langsvr::lsp {
+namespace {
+
+/// @returns the byte offset of the UTF-16 position @p pos in @p text
+size_t OffsetOf(const std::string& text, const Position& pos) {
+    size_t offset = 0;
+    for (Uinteger line = 0; line < pos.line; line++) {
+        while (offset < text.size() && text[offset] != '\n' && text[offset] != '\r') {
+            offset++;
+        }
+        if (offset < text.size() && text[offset] == '\r' && offset + 1 < text.size() &&
+            text[offset + 1] == '\n') {
+            offset++;
+        }
+        offset++;
+    }
+    for (Uinteger units = 0; units < pos.character;) {
+        auto byte = static_cast<unsigned char>(text[offset]);
+        units += byte >= 0xf0 ? 2 : 1;
+        offset++;
+        while (offset < text.size() && (static_cast<unsigned char>(text[offset]) & 0xc0) == 0x80) {
+            offset++;
+        }
+    }
+    return offset;
+}
+
+/// @returns @p text with the @p changes applied in order
+std::string Apply(std::string text, const std::vector<TextDocumentContentChangePartial>& changes) {
+    for (auto& change : changes) {
+        size_t start = OffsetOf(text, change.range.start);
+        size_t end = OffsetOf(text, change.range.end);
+        text = text.substr(0, start) + change.text + text.substr(end);
+    }
+    return text;
+}
+
+/// @returns @p range formatted as 'line:character-line:character'
+std::string Str(const Range& range) {
+    return std::to_string(range.start.line) + ":" + std::to_string(range.start.character) + "-" +
+           std::to_string(range.end.line) + ":" + std::to_string(range.end.character);
+}
+
+TEST(TextDocumentSyncTest, Equal) {
+    EXPECT_TRUE(OptimalTextDocumentSync("", "").empty());
+    EXPECT_TRUE(OptimalTextDocumentSync("a\nb\n", "a\nb\n").empty());
+}
+
+TEST(TextDocumentSyncTest, SingleCharacter) {
+    auto changes = OptimalTextDocumentSync("one\ntwo\nthree\n", "one\ntwi\nthree\n");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "1:2-1:3");
+    EXPECT_EQ(changes[0].text, "i");
+}
+
+TEST(TextDocumentSyncTest, InsertLine) {
+    auto changes = OptimalTextDocumentSync("one\nthree\n", "one\ntwo\nthree\n");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "1:0-1:0");
+    EXPECT_EQ(changes[0].text, "two\n");
+}
+
+TEST(TextDocumentSyncTest, DeleteLastLine) {
+    auto changes = OptimalTextDocumentSync("one\ntwo", "one\n");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "1:0-1:3");
+    EXPECT_EQ(changes[0].text, "");
+}
+
+TEST(TextDocumentSyncTest, MultipleHunksAreOrderedLastFirst) {
+    std::string original = "a\nb\nc\nd\ne\nf\ng\n";
+    std::string revised = "a\nB\nc\nd\ne\nF\ng\n";
+    auto changes = OptimalTextDocumentSync(original, revised);
+    ASSERT_EQ(changes.size(), 2u);
+    EXPECT_EQ(Str(changes[0].range), "5:0-5:1");
+    EXPECT_EQ(changes[0].text, "F");
+    EXPECT_EQ(Str(changes[1].range), "1:0-1:1");
+    EXPECT_EQ(changes[1].text, "B");
+    EXPECT_EQ(Apply(original, changes), revised);
+}
+
+TEST(TextDocumentSyncTest, UTF16Positions) {
+    // '😀' is 4 UTF-8 bytes and 2 UTF-16 code units. 'é' is 2 UTF-8 bytes and 1 UTF-16 code unit.
+    auto changes = OptimalTextDocumentSync("😀é x\n", "😀é y\n");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "0:4-0:5");
+    EXPECT_EQ(changes[0].text, "y");
+}
+
+TEST(TextDocumentSyncTest, OtherEncodings) {
+    auto utf8 = OptimalTextDocumentSync("😀é x\n", "😀é y\n", PositionEncodingKind::kUTF8);
+    ASSERT_EQ(utf8.size(), 1u);
+    EXPECT_EQ(Str(utf8[0].range), "0:7-0:8");
+
+    auto utf32 = OptimalTextDocumentSync("😀é x\n", "😀é y\n", PositionEncodingKind::kUTF32);
+    ASSERT_EQ(utf32.size(), 1u);
+    EXPECT_EQ(Str(utf32[0].range), "0:3-0:4");
+}
+
+TEST(TextDocumentSyncTest, DoesNotSplitCodePoints) {
+    // 'é' (C3 A9) and 'è' (C3 A8) share their first UTF-8 byte
+    auto changes = OptimalTextDocumentSync("é", "è");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "0:0-0:1");
+    EXPECT_EQ(changes[0].text, "è");
+}
+
+TEST(TextDocumentSyncTest, DoesNotSplitCRLF) {
+    auto changes = OptimalTextDocumentSync("a\r\nb", "a\rb");
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "0:1-1:0");
+    EXPECT_EQ(changes[0].text, "\r");
+}
+
+TEST(TextDocumentSyncTest, LeadingContinuationByte) {
+    // Invalid UTF-8: the documents start with a continuation byte that has no lead byte
+    std::string original = "\x80\x80x\n";
+    std::string revised = "\x80\x81x\n";
+    auto changes = OptimalTextDocumentSync(original, revised, PositionEncodingKind::kUTF8);
+    ASSERT_EQ(changes.size(), 1u);
+    EXPECT_EQ(Str(changes[0].range), "0:0-0:2");
+    EXPECT_EQ(changes[0].text, "\x80\x81");
+}
+
+TEST(TextDocumentSyncTest, RandomEdits) {
+    const std::vector<std::string> fragments{"a", "b", "\n", "\r\n", "é", "😀", "xyz\n"};
+    std::mt19937 rng(42);
+    auto random_text = [&](size_t max_len) {
+        std::string text;
+        for (size_t i = 0, n = rng() % max_len; i < n; i++) {
+            text += fragments[rng() % fragments.size()];
+        }
+        return text;
+    };
+    for (int i = 0; i < 500; i++) {
+        std::string original = random_text(40);
+        std::string revised = original;
+        for (int edits = rng() % 4; edits >= 0; edits--) {
+            // Replace a random line-aligned region, so the edits are realistic
+            size_t at = revised.empty() ? 0 : rng() % revised.size();
+            while (at > 0 && !(revised[at - 1] == '\n')) {
+                at--;
+            }
+            size_t len = std::min<size_t>(rng() % 8, revised.size() - at);
+            while (at + len < revised.size() &&
+                   (static_cast<unsigned char>(revised[at + len]) & 0xc0) == 0x80) {
+                len++;
+            }
+            revised = revised.substr(0, at) + random_text(6) + revised.substr(at + len);
+        }
+        SCOPED_TRACE(original + " -> " + revised);
+        EXPECT_EQ(Apply(original, OptimalTextDocumentSync(original, revised)), revised);
+    }
+}
+
+/// @returns a TextEdit replacing the range @p start_line:@p start_char-@p end_line:@p end_char with
+/// @p text
+TextEdit Edit(Uinteger start_line,
+              Uinteger start_char,
+              Uinteger end_line,
+              Uinteger end_char,
+              std::string text) {
+    return TextEdit{Range{{start_line, start_char}, {end_line, end_char}}, std::move(text)};
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEdits) {
+    std::vector<TextEdit> edits{
+        Edit(1, 4, 1, 7, "baz"),
+        Edit(0, 0, 0, 5, "goodbye"),
+        Edit(1, 0, 1, 0, "> "),
+    };
+    auto got = SafeApplyTextEdits("hello world\nfoo bar\n", edits);
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "goodbye world\n> foo baz\n");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsInsertOrder) {
+    auto got = SafeApplyTextEdits("ab", {Edit(0, 1, 0, 1, "1"), Edit(0, 1, 0, 1, "2"),
+                                         Edit(0, 0, 0, 1, "A"), Edit(0, 1, 0, 1, "3")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "A123b");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsClamps) {
+    auto got = SafeApplyTextEdits("ab\r\ncd", {Edit(0, 1, 0, 99, "X"), Edit(1, 1, 5, 0, "Y")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "aX\r\ncY");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsEncodings) {
+    EXPECT_EQ(SafeApplyTextEdits("é😀x", {Edit(0, 3, 0, 4, "y")}).Get(), "é😀y");
+    EXPECT_EQ(
+        SafeApplyTextEdits("é😀x", {Edit(0, 2, 0, 3, "y")}, PositionEncodingKind::kUTF32).Get(),
+        "é😀y");
+    EXPECT_EQ(
+        SafeApplyTextEdits("é😀x", {Edit(0, 6, 0, 7, "y")}, PositionEncodingKind::kUTF8).Get(),
+        "é😀y");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsDuplicates) {
+    auto got = SafeApplyTextEdits("abc", {Edit(0, 1, 0, 2, "B"), Edit(0, 1, 0, 2, "B")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "aBc");
+
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 3, 0, 4, "Y"), Edit(0, 1, 0, 3, "bX"),
+                                        Edit(0, 3, 0, 4, "Y"), Edit(0, 1, 0, 3, "bX")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "abXYef");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsAdjacent) {
+    auto got = SafeApplyTextEdits("abcdef", {Edit(0, 3, 0, 5, "Y"), Edit(0, 5, 0, 5, "Z"),
+                                             Edit(0, 1, 0, 3, "X")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "aXYZf");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsNested) {
+    // The inner edit agrees with the outer edit
+    auto got = SafeApplyTextEdits("abcdef", {Edit(0, 2, 0, 4, "XY"), Edit(0, 1, 0, 5, "bXYe")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "abXYef");
+
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 1, 0, 5, "bXde"), Edit(0, 1, 0, 3, "bX")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "abXdef");
+
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 1, 0, 5, "bcYde"), Edit(0, 3, 0, 3, "Y")});
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), "abcYdef");
+
+    // The inner edit disagrees with the outer edit
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 1, 0, 5, "bXYe"), Edit(0, 2, 0, 4, "XZ")});
+    ASSERT_NE(got, Success);
+    EXPECT_EQ(got.Failure().reason, "text edit 0:2-0:4 overlaps text edit 0:1-0:5");
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsOverlap) {
+    auto got = SafeApplyTextEdits("abcdef", {Edit(0, 3, 0, 5, "X"), Edit(0, 1, 0, 4, "Y")});
+    ASSERT_NE(got, Success);
+    EXPECT_EQ(got.Failure().reason, "text edit 0:3-0:5 overlaps text edit 0:1-0:4");
+
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 1, 0, 4, "X"), Edit(0, 1, 0, 4, "Y")});
+    EXPECT_NE(got, Success);
+
+    got = SafeApplyTextEdits("abcdef", {Edit(0, 1, 0, 4, "X"), Edit(0, 2, 0, 2, "Y")});
+    EXPECT_NE(got, Success);
+}
+
+TEST(TextDocumentSyncTest, SafeApplyTextEditsInvertedRange) {
+    auto got = SafeApplyTextEdits("abc", {Edit(0, 2, 0, 1, "")});
+    ASSERT_NE(got, Success);
+    EXPECT_EQ(got.Failure().reason, "text edit range 0:2-0:1 ends before it starts");
+}
+
+TEST(TextDocumentSyncTest, ApplyOptimalTextDocumentSync) {
+    std::string original = "one\ntwo\nthree\n";
+    std::string revised = "one\n2\nthree\nfour\n";
+    std::vector<TextEdit> edits;
+    for (auto& change : OptimalTextDocumentSync(original, revised)) {
+        edits.push_back(TextEdit{change.range, change.text});
+    }
+    auto got = SafeApplyTextEdits(original, edits);
+    ASSERT_EQ(got, Success);
+    EXPECT_EQ(got.Get(), revised);
+}
+
+/// @returns the range of the word at @p position in @p content formatted as
+/// 'line:character-line:character', "none" if there is no word, or "error" on failure
+std::string WordAt(std::string_view content,
+                   Position position,
+                   PositionEncodingKind encoding = PositionEncodingKind::kUTF16,
+                   const WordPredicate& is_word = IsIdentifierCodePoint) {
+    auto range = WordRangeAt(content, position, encoding, is_word);
+    if (range != Success) {
+        return "error";
+    }
+    if (!range.Get()) {
+        return "none";
+    }
+    auto& r = *range.Get();
+    return std::to_string(r.start.line) + ":" + std::to_string(r.start.character) + "-" +
+           std::to_string(r.end.line) + ":" + std::to_string(r.end.character);
+}
+
+TEST(TextDocumentSyncTest, WordRangeAt) {
+    std::string_view text = "int foo_bar = baz(1);\nreturn x;\n";
+    EXPECT_EQ(WordAt(text, Position{0, 0}), "0:0-0:3");
+    EXPECT_EQ(WordAt(text, Position{0, 3}), "0:0-0:3");  // End of word
+    EXPECT_EQ(WordAt(text, Position{0, 6}), "0:4-0:11");
+    EXPECT_EQ(WordAt(text, Position{0, 12}), "none");
+    EXPECT_EQ(WordAt(text, Position{0, 14}), "0:14-0:17");
+    EXPECT_EQ(WordAt(text, Position{0, 18}), "0:18-0:19");
+    EXPECT_EQ(WordAt(text, Position{1, 0}), "1:0-1:6");
+    EXPECT_EQ(WordAt(text, Position{1, 9}), "none");
+    EXPECT_EQ(WordAt(text, Position{2, 0}), "none");
+}
+
+TEST(TextDocumentSyncTest, WordRangeAtUnicode) {
+    // 'λ' is 2 UTF-8 bytes and 1 UTF-16 unit. '😀' is 4 UTF-8 bytes and 2 UTF-16 units.
+    std::string_view text = "😀 λx·y";
+    EXPECT_EQ(WordAt(text, Position{0, 0}), "none");
+    EXPECT_EQ(WordAt(text, Position{0, 3}), "0:3-0:5");
+    EXPECT_EQ(WordAt(text, Position{0, 6}), "0:6-0:7");
+    EXPECT_EQ(WordAt(text, Position{0, 5}, PositionEncodingKind::kUTF8), "0:5-0:8");
+    EXPECT_EQ(WordAt(text, Position{0, 3}, PositionEncodingKind::kUTF32), "0:2-0:4");
+}
+
+TEST(TextDocumentSyncTest, WordRangeAtCustomPredicate) {
+    auto kebab = [](char32_t c) { return IsIdentifierCodePoint(c) || c == '-'; };
+    EXPECT_EQ(WordAt("(font-size: 1)", Position{0, 3}), "0:1-0:5");
+    EXPECT_EQ(WordAt("(font-size: 1)", Position{0, 3}, PositionEncodingKind::kUTF16, kebab),
+              "0:1-0:10");
+}
+
+TEST(TextDocumentSyncTest, WordRangeAtInvalidPosition) {
+    EXPECT_EQ(WordAt("abc\ndef", Position{0, 4}), "error");
+    EXPECT_EQ(WordAt("abc\ndef", Position{5, 0}), "error");
+    EXPECT_EQ(WordAt("😀", Position{0, 1}), "error");  // Inside a surrogate pair
+    EXPECT_EQ(WordAt("", Position{0, 0}), "none");
+}
+
+}  // namespace
+}  // namespace langsvr::lsp
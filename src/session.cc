@@ -26,15 +26,103 @@
 // OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 
 #include "langsvr/session.h"
+
+#include <algorithm>
+#include <cstdint>
+#include <cstdio>
+#include <exception>
+#include <string>
+#include <vector>
+
 #include "langsvr/json/builder.h"
+#include "langsvr/lsp/response_error.h"
+#include "src/utils/random.h"
 
 namespace langsvr {
+namespace {
+
+/// The maximum number of bytes of a malformed message included in the parse failure
+static constexpr size_t kMaxExcerptLength = 256;
+
+/// @returns the first kMaxExcerptLength bytes of @p json, with the bytes that are not printable
+/// ASCII characters escaped, so that the excerpt can be safely written to a log.
+std::string Excerpt(std::string_view json) {
+    std::string out;
+    for (char c : json.substr(0, kMaxExcerptLength)) {
+        auto byte = static_cast<unsigned char>(c);
+        switch (c) {
+            case '\n':
+                out += "\\n";
+                break;
+            case '\r':
+                out += "\\r";
+                break;
+            case '\t':
+                out += "\\t";
+                break;
+            case '\\':
+                out += "\\\\";
+                break;
+            default:
+                if (byte < 0x20 || byte >= 0x7f) {
+                    char escaped[5];
+                    std::snprintf(escaped, sizeof(escaped), "\\x%02x", byte);
+                    out += escaped;
+                } else {
+                    out += c;
+                }
+                break;
+        }
+    }
+    if (json.size() > kMaxExcerptLength) {
+        out += "... (" + std::to_string(json.size()) + " bytes)";
+    }
+    return out;
+}
+
+/// Invoke calls @p f, returning its result. If @p recover is true, then an exception thrown by @p f
+/// is passed to @p recovered, which returns the failure to return instead.
+template <typename F, typename R>
+auto Invoke(bool recover, F&& f, R&& recovered) -> decltype(f()) {
+#if defined(__cpp_exceptions)
+    if (recover) {
+        try {
+            return f();
+        } catch (const std::exception& e) {
+            return recovered(e.what());
+        } catch (...) {
+            return recovered("unknown exception");
+        }
+    }
+#else
+    (void)recover;
+    (void)recovered;
+#endif
+    return f();
+}
+
+/// @returns a new random correlation id
+std::string NewCorrelationId() {
+    char id[17];
+    std::snprintf(id, sizeof(id), "%016llx", static_cast<unsigned long long>(RandomU64()));
+    return id;
+}
+
+}  // namespace
 
 Result<SuccessType> Session::Receive(std::string_view json) {
+    if (!checked_proposed_) {
+        checked_proposed_ = true;
+        WarnProposedHandlers();
+    }
+
     auto json_builder = json::Builder::Create();
     auto object = json_builder->Parse(json);
     if (object != Success) {
-        return object.Failure();
+        // Trim the trailing line break of the parser's error, so the excerpt follows the separator
+        auto reason = object.Failure().reason;
+        reason.erase(reason.find_last_not_of(" \n") + 1);
+        return Failure{"failed to parse message: " + reason + "; message: '" + Excerpt(json) + "'"};
     }
 
     auto method = object.Get()->Get<json::String>("method");
@@ -53,15 +141,48 @@ Result<SuccessType> Session::Receive(std::string_view json) {
             return Failure{"no handler registered for request method '" + method.Get() + "'"};
         }
         auto& request_handler = it->second;
+        if (auto res = CheckDirection(method.Get(), request_handler.direction); res != Success) {
+            // Reply with a MethodNotFound error, so that the peer is not left waiting
+            lsp::ResponseError<lsp::Null> error;
+            error.code = lsp::ErrorCodes::kMethodNotFound;
+            error.message = res.Failure().reason;
+            auto error_json = Encode(error, *json_builder.get());
+            if (error_json != Success) {
+                return error_json.Failure();
+            }
+            std::vector response_members{
+                json::Builder::Member{"id", json_builder->I64(id.Get())},
+                json::Builder::Member{"error", error_json.Get()},
+            };
+            auto* response = json_builder->Object(response_members);
+            if (auto sent = SendJson(response->Json()); sent != Success) {
+                return sent.Failure();
+            }
+            return res.Failure();
+        }
 
         std::vector response_members{
             json::Builder::Member{"id", json_builder->I64(id.Get())},
         };
 
-        if (auto result = request_handler.function(*object.Get(), *json_builder.get());
-            result == Success) {
-            if (auto* result_json = result.Get()) {
-                response_members.push_back(json::Builder::Member{"result", result.Get()});
+        auto result = Invoke(
+            recover_from_exceptions_,
+            [&] { return request_handler.function(*object.Get(), *json_builder.get()); },
+            [&](std::string_view what) -> Result<RequestResponse> {
+                lsp::ResponseError<lsp::Null> error;
+                error.code = lsp::ErrorCodes::kInternalError;
+                error.message = Recovered(method.Get(), what);
+                auto error_json = Encode(error, *json_builder.get());
+                if (error_json != Success) {
+                    return error_json.Failure();
+                }
+                return RequestResponse{nullptr, error_json.Get()};
+            });
+        if (result == Success) {
+            if (auto* error_json = result->error) {
+                response_members.push_back(json::Builder::Member{"error", error_json});
+            } else if (auto* result_json = result->result) {
+                response_members.push_back(json::Builder::Member{"result", result_json});
             }
         } else {
             auto err = result.Failure().reason;
@@ -82,12 +203,68 @@ Result<SuccessType> Session::Receive(std::string_view json) {
             return Failure{"no handler registered for request method '" + method.Get() + "'"};
         }
         auto& notification_handler = it->second;
-        return notification_handler.function(*object.Get());
+        if (auto res = CheckDirection(method.Get(), notification_handler.direction);
+            res != Success) {
+            return res.Failure();
+        }
+        return Invoke(
+            recover_from_exceptions_, [&] { return notification_handler.function(*object.Get()); },
+            [&](std::string_view what) { return Failure{Recovered(method.Get(), what)}; });
     }
 
     return Success;
 }
 
+Result<SuccessType> Session::CheckDirection(std::string_view method,
+                                            lsp::MessageDirection direction) {
+    auto expected = role_ == Role::kServer ? lsp::MessageDirection::kClientToServer
+                                           : lsp::MessageDirection::kServerToClient;
+    if (direction == lsp::MessageDirection::kBoth || direction == expected) {
+        return Success;
+    }
+
+    std::string msg = "'" + std::string(method) + "' is not sent from the " +
+                      (role_ == Role::kServer ? "client to the server" : "server to the client");
+    if (warning_handler_) {
+        warning_handler_(msg);
+    }
+    if (permissive_direction_) {
+        return Success;
+    }
+    return Failure{msg};
+}
+
+void Session::WarnProposedHandlers() {
+    if (allow_proposed_ || !warning_handler_) {
+        return;
+    }
+    std::vector<std::string> methods;
+    for (auto& [method, handler] : request_handlers_) {
+        if (handler.proposed) {
+            methods.push_back(method);
+        }
+    }
+    for (auto& [method, handler] : notification_handlers_) {
+        if (handler.proposed) {
+            methods.push_back(method);
+        }
+    }
+    std::sort(methods.begin(), methods.end());
+    for (auto& method : methods) {
+        warning_handler_("handler for method '" + method +
+                         "' is a proposed LSP feature and may change");
+    }
+}
+
+std::string Session::Recovered(std::string_view method, std::string_view what) {
+    auto id = NewCorrelationId();
+    if (warning_handler_) {
+        warning_handler_("handler for '" + std::string(method) +
+                         "' threw an exception (correlation id: " + id + "): " + std::string(what));
+    }
+    return "internal error (correlation id: " + id + ")";
+}
+
 Result<SuccessType> Session::SendJson(std::string_view msg) {
     if (!sender_) [[unlikely]] {
         return Failure{"no sender set"};
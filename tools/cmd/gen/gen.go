@@ -30,6 +30,7 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
@@ -40,6 +41,7 @@ import (
 	"time"
 
 	"github.com/google/langsvr/tools/cmd/gen/json"
+	"github.com/google/langsvr/tools/cmd/gen/protocol"
 	"github.com/google/langsvr/tools/cmd/gen/resolver"
 	"github.com/google/langsvr/tools/fileutils"
 	"github.com/google/langsvr/tools/template"
@@ -59,59 +61,150 @@ func main() {
 	}
 }
 
+// stringList is a flag.Value that holds the values of a repeated flag
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func run() error {
-	projectRoot := fileutils.ProjectRoot()
+	extensions := stringList{}
+	flag.Var(&extensions, "extensions", "path to a meta model JSON file declaring vendor extension methods and types. May be repeated")
+	minVersion := flag.String("min-version", lspVersion, "the LSP version that is always compiled. Requests and notifications introduced after this version are guarded by a LANGSVR_LSP_<major>_<minor> macro. An empty version guards nothing")
+	emitDts := flag.String("emit-dts", "", "path of a TypeScript declaration file (e.g. 'protocol.d.ts') to generate from the meta model, alongside the C++ files")
+	flag.Parse()
 
-	jsonPath := filepath.Join(projectRoot, "third_party/lsprotocol/generator/lsp.json")
-	jsonFile, err := os.Open(jsonPath)
+	funcs, err := functions(*minVersion)
 	if err != nil {
 		return err
 	}
-	defer jsonFile.Close()
 
-	model, err := json.Decode(jsonFile)
+	projectRoot := fileutils.ProjectRoot()
+
+	model, err := loadModel(filepath.Join(projectRoot, "third_party/lsprotocol/generator/lsp.json"))
 	if err != nil {
 		return err
 	}
 
+	for _, path := range extensions {
+		ext, err := loadModel(path)
+		if err != nil {
+			return err
+		}
+		if model, err = json.Merge(model, ext); err != nil {
+			return fmt.Errorf("while merging extensions '%v': %w", path, err)
+		}
+	}
+
 	protocol, err := resolver.Resolve(model)
 	if err != nil {
 		return err
 	}
 
+	generated := map[string]string{}
 	for _, relPath := range []string{"include/langsvr/lsp/lsp.h", "src/lsp/lsp.cc"} {
 		tmplRelPath := relPath + ".tmpl"
 		t, err := template.FromFile(filepath.Join(projectRoot, tmplRelPath))
 		if err != nil {
 			return err
 		}
-		outPath := filepath.Join(projectRoot, relPath)
-
 		// Load the old file
-		existing, err := os.ReadFile(outPath)
+		existing, err := os.ReadFile(filepath.Join(projectRoot, relPath))
 		if err != nil {
 			existing = nil
 		}
 
 		buffer := &bytes.Buffer{}
 		buffer.WriteString(header(string(existing), filepath.ToSlash(tmplRelPath), "//"))
-		if err := t.Run(buffer, protocol, template.Functions{}); err != nil {
+		if err := t.Run(buffer, protocol, funcs); err != nil {
 			return err
 		}
+		src := buffer.String()
+		if relPath == "include/langsvr/lsp/lsp.h" {
+			src = withSurfaceHash(src)
+		}
 
-		formatted, err := ClangFormat(buffer.String())
+		formatted, err := ClangFormat(src)
 		if err != nil {
 			return err
 		}
+		generated[relPath] = formatted
+	}
 
-		if err := os.WriteFile(outPath, []byte(formatted), 0666); err != nil {
+	// Only write the files once they are known to be valid, so that a failure
+	// does not leave invalid generated files behind
+	if problems := validate(protocol, generated["include/langsvr/lsp/lsp.h"], generated["src/lsp/lsp.cc"]); len(problems) > 0 {
+		return fmt.Errorf("generated code does not match the meta model (%v problems):\n  %v",
+			len(problems), strings.Join(problems, "\n  "))
+	}
+	for relPath, content := range generated {
+		if err := os.WriteFile(filepath.Join(projectRoot, relPath), []byte(content), 0666); err != nil {
 			return err
 		}
+	}
 
+	if *emitDts != "" {
+		if err := writeDts(protocol, funcs, *emitDts); err != nil {
+			return fmt.Errorf("-emit-dts: %w", err)
+		}
 	}
 	return nil
 }
 
+// dtsTemplate is the project-relative path of the template used to generate
+// the TypeScript declaration file
+const dtsTemplate = "tools/cmd/gen/protocol.d.ts.tmpl"
+
+// generateDts returns the TypeScript declarations of the protocol p, without
+// the file header
+func generateDts(p *protocol.Protocol, funcs template.Functions) (string, error) {
+	t, err := template.FromFile(filepath.Join(fileutils.ProjectRoot(), dtsTemplate))
+	if err != nil {
+		return "", err
+	}
+	sb := strings.Builder{}
+	if err := t.Run(&sb, p, funcs); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeDts writes the TypeScript declaration file of the protocol p to
+// outPath. Unlike the C++ files, the declaration file is not formatted.
+func writeDts(p *protocol.Protocol, funcs template.Functions, outPath string) error {
+	dts, err := generateDts(p, funcs)
+	if err != nil {
+		return err
+	}
+
+	// Load the old file
+	existing, err := os.ReadFile(outPath)
+	if err != nil {
+		existing = nil
+	}
+
+	return os.WriteFile(outPath, []byte(header(string(existing), dtsTemplate, "//")+dts), 0666)
+}
+
+// loadModel decodes the meta model JSON file at path
+func loadModel(path string) (json.MetaModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return json.MetaModel{}, err
+	}
+	defer file.Close()
+
+	model, err := json.Decode(file)
+	if err != nil {
+		return json.MetaModel{}, fmt.Errorf("while decoding '%v': %w", path, err)
+	}
+	return model, nil
+}
+
 var re = regexp.MustCompile(`• Copyright (\d+) The`)
 
 // header returns the header text to emit at the top of the file.
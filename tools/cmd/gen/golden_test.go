@@ -0,0 +1,198 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/langsvr/tools/fileutils"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// The golden files are generated from testdata/mini.json, a small meta model
+// that holds one of each kind of declaration supported by the generator.
+// New generator features should extend mini.json so that they are covered by
+// these tests. The golden files are the raw template output, without
+// clang-format.
+//
+// To update the golden files run: 'go test ./tools/cmd/gen -update'
+const (
+	goldenModel  = "testdata/mini.json"
+	goldenHeader = "testdata/lsp.h.golden"
+	goldenSource = "testdata/lsp.cc.golden"
+	goldenDts    = "testdata/protocol.d.ts.golden"
+)
+
+func TestGolden(t *testing.T) {
+	model, err := os.ReadFile(filepath.Join(fileutils.ThisDir(), goldenModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, header, source := generate(t, string(model))
+	funcs, err := functions("")
+	if err != nil {
+		t.Fatalf("functions() failed with %v", err)
+	}
+	dts, err := generateDts(p, funcs)
+	if err != nil {
+		t.Fatalf("generateDts() failed with %v", err)
+	}
+
+	for _, golden := range []struct {
+		path string
+		got  string
+	}{
+		{goldenHeader, header},
+		{goldenSource, source},
+		{goldenDts, dts},
+	} {
+		path := filepath.Join(fileutils.ThisDir(), golden.path)
+		if *update {
+			if err := os.WriteFile(path, []byte(golden.got), 0666); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expect, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if golden.got != string(expect) {
+			t.Errorf("generated output differs from '%v'. Run 'go test ./tools/cmd/gen -update' to update", golden.path)
+		}
+	}
+
+	if problems := validate(p, header, source); len(problems) > 0 {
+		t.Errorf("validate() returned problems: %v", problems)
+	}
+}
+
+var (
+	dtsInterfaceRE = regexp.MustCompile(`export interface (\w+)(?: extends ([^{]+))? {`)
+	cppStructRE    = regexp.MustCompile(`struct (\w+)(?: : ([^{]+))? {`)
+)
+
+// TestGoldenBasesMatch checks that each C++ structure derives from the same
+// structures, in the same order, as the TypeScript interface extends.
+func TestGoldenBasesMatch(t *testing.T) {
+	read := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(fileutils.ThisDir(), path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	bases := func(re *regexp.Regexp, src, prefix string) map[string]string {
+		out := map[string]string{}
+		for _, m := range re.FindAllStringSubmatch(src, -1) {
+			list := []string{}
+			for _, base := range strings.Split(m[2], ",") {
+				if base = strings.TrimSpace(base); base != "" {
+					list = append(list, strings.TrimPrefix(base, prefix))
+				}
+			}
+			out[m[1]] = strings.Join(list, ", ")
+		}
+		return out
+	}
+
+	dts := bases(dtsInterfaceRE, read(goldenDts), "")
+	cpp := bases(cppStructRE, read(goldenHeader), "lsp::")
+	for name, expect := range dts {
+		if got, ok := cpp[name]; !ok {
+			t.Errorf("interface '%v' has no C++ structure", name)
+		} else if got != expect {
+			t.Errorf("structure '%v' has bases '%v', expected '%v'", name, got, expect)
+		}
+	}
+	if !strings.Contains(dts["WorkspaceSymbolParams"], "WorkDoneProgressParams") {
+		t.Errorf("'%v' does not cover a structure with a mixin", goldenModel)
+	}
+}
+
+// TestGoldenCompiles checks that the golden files are valid C++, if a C++
+// compiler is available.
+func TestGoldenCompiles(t *testing.T) {
+	compiler := ""
+	for _, name := range []string{"clang++", "g++"} {
+		if path, err := exec.LookPath(name); err == nil {
+			compiler = path
+			break
+		}
+	}
+	if compiler == "" {
+		t.Skip("no C++ compiler found")
+	}
+
+	dir := t.TempDir()
+	header, err := os.ReadFile(filepath.Join(fileutils.ThisDir(), goldenHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.ReadFile(filepath.Join(fileutils.ThisDir(), goldenSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "langsvr/lsp"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "langsvr/lsp/lsp.h"), header, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lsp.cc"), source, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(compiler, "-std=c++20", "-fsyntax-only",
+		"-I"+dir,
+		"-I"+filepath.Join(fileutils.ProjectRoot(), "include"),
+		filepath.Join(dir, "lsp.cc"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%v failed to compile the golden files:\n%v\n%v", filepath.Base(compiler), string(out), err)
+	}
+}
+
+// TestGoldenDtsCompiles checks that the golden TypeScript declaration file is
+// valid TypeScript, if the TypeScript compiler is available.
+func TestGoldenDtsCompiles(t *testing.T) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not found")
+	}
+	cmd := exec.Command(tsc, "--noEmit", "--strict", filepath.Join(fileutils.ThisDir(), goldenDts))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("tsc failed to compile '%v':\n%v\n%v", goldenDts, string(out), err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// surfaceHashRE matches the declaration of kProtocolSurfaceHash in the
+// generated header. The first group is everything up to the value.
+var surfaceHashRE = regexp.MustCompile(`(kProtocolSurfaceHash\s*=\s*)"[0-9a-f]*"`)
+
+// surfaceHash returns a hex-encoded SHA-256 hash of the protocol surface
+// declared by the generated header. The hash covers the C++ declarations of
+// the header: the method names, message directions and flags of the requests
+// and notifications, and the names, members and types of all the other
+// declarations. Comments and whitespace are ignored, so documentation changes
+// and reformatting do not change the hash. The value of kProtocolSurfaceHash
+// is ignored too, so the hash of a checked-in header can be recomputed from
+// the header alone.
+func surfaceHash(header string) string {
+	header = surfaceHashRE.ReplaceAllString(header, `$1""`)
+	hash := sha256.New()
+	hash.Write([]byte(stripCommentsAndWhitespace(header)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// withSurfaceHash returns header with the value of kProtocolSurfaceHash set
+// to surfaceHash(header)
+func withSurfaceHash(header string) string {
+	return surfaceHashRE.ReplaceAllString(header, `$1"`+surfaceHash(header)+`"`)
+}
+
+// stripCommentsAndWhitespace returns the C++ source src with the line comments
+// and the whitespace outside of string literals removed. Block comments are
+// kept, as the generated enum entries hold their values in block comments.
+func stripCommentsAndWhitespace(src string) string {
+	sb := strings.Builder{}
+	inString := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case inString:
+			sb.WriteByte(c)
+			if c == '\\' && i+1 < len(src) {
+				i++
+				sb.WriteByte(src[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			sb.WriteByte(c)
+		case c == '/' && strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/langsvr/tools/fileutils"
+)
+
+const hashModel = `{
+	"metaData": { "version": "3.17.0" },
+	"requests": [{
+		"method": "textDocument/hover",
+		"messageDirection": "clientToServer",
+		"params": { "kind": "reference", "name": "HoverParams" },
+		"result": { "kind": "reference", "name": "Hover" }
+	}],
+	"notifications": [],
+	"structures": [
+		{
+			"name": "Hover",
+			"documentation": "The result of a hover request.",
+			"properties": [
+				{ "name": "contents", "type": { "kind": "base", "name": "string" } }
+			]
+		},
+		{
+			"name": "HoverParams",
+			"properties": [
+				{ "name": "line", "type": { "kind": "base", "name": "uinteger" } }
+			]
+		}
+	],
+	"enumerations": [],
+	"typeAliases": []
+}`
+
+// headerSurfaceHash returns the surface hash of the header generated from the
+// meta model JSON
+func headerSurfaceHash(t *testing.T, model string) string {
+	t.Helper()
+	_, header, _ := generate(t, model)
+	return surfaceHash(header)
+}
+
+func TestSurfaceHashStable(t *testing.T) {
+	a, b := headerSurfaceHash(t, hashModel), headerSurfaceHash(t, hashModel)
+	if a != b {
+		t.Errorf("hash of identical models differ: %v != %v", a, b)
+	}
+	if got, expect := len(a), 64; got != expect {
+		t.Errorf("hash length was %v, expected %v", got, expect)
+	}
+}
+
+func TestSurfaceHashOfHeader(t *testing.T) {
+	_, header, _ := generate(t, hashModel)
+	hash := surfaceHash(header)
+	if !strings.Contains(header, `"`+hash+`"`) {
+		t.Errorf("generated header does not declare kProtocolSurfaceHash as %v", hash)
+	}
+
+	// The hash ignores its own value, comments and formatting
+	for _, test := range []struct {
+		name   string
+		header string
+	}{
+		{"value", strings.Replace(header, hash, strings.Repeat("0", 64), 1)},
+		{"comment", strings.Replace(header, "/// The result of a hover request.", "// Hover result", 1)},
+		{"formatting", strings.ReplaceAll(header, "\n", "\n\n  ")},
+	} {
+		if test.header == header {
+			t.Fatalf("changing the %v did not modify the header", test.name)
+		}
+		if got := surfaceHash(test.header); got != hash {
+			t.Errorf("changing the %v changed the hash from %v to %v", test.name, hash, got)
+		}
+	}
+}
+
+// TestCheckedInSurfaceHash checks that kProtocolSurfaceHash of the checked-in
+// lsp.h is the hash of its declarations, so that the header is not left stale
+// by a change made without re-running the generator.
+func TestCheckedInSurfaceHash(t *testing.T) {
+	header, err := os.ReadFile(filepath.Join(fileutils.ProjectRoot(), "include/langsvr/lsp/lsp.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expect := string(header), withSurfaceHash(string(header)); got != expect {
+		t.Errorf("kProtocolSurfaceHash of lsp.h is stale, expected %v", surfaceHash(string(header)))
+	}
+}
+
+func TestSurfaceHashSensitivity(t *testing.T) {
+	const contents = `{ "name": "contents", "type": { "kind": "base", "name": "string" } }`
+	for _, test := range []struct {
+		name    string
+		old     string
+		new     string
+		changed bool
+	}{
+		{
+			name:    "add field",
+			old:     contents,
+			new:     contents + `, { "name": "range", "type": { "kind": "base", "name": "string" } }`,
+			changed: true,
+		},
+		{
+			name:    "remove field",
+			old:     contents,
+			new:     ``,
+			changed: true,
+		},
+		{
+			name:    "change field type",
+			old:     `{ "kind": "base", "name": "string" }`,
+			new:     `{ "kind": "array", "element": { "kind": "base", "name": "string" } }`,
+			changed: true,
+		},
+		{
+			name:    "make field optional",
+			old:     contents,
+			new:     `{ "name": "contents", "optional": true, "type": { "kind": "base", "name": "string" } }`,
+			changed: true,
+		},
+		{
+			name:    "change method direction",
+			old:     `"clientToServer"`,
+			new:     `"both"`,
+			changed: true,
+		},
+		{
+			name:    "change documentation",
+			old:     `"The result of a hover request."`,
+			new:     `"The hover result."`,
+			changed: false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if !strings.Contains(hashModel, test.old) {
+				t.Fatalf("model does not contain '%v'", test.old)
+			}
+			modified := strings.Replace(hashModel, test.old, test.new, 1)
+			if changed := headerSurfaceHash(t, hashModel) != headerSurfaceHash(t, modified); changed != test.changed {
+				t.Errorf("hash changed: %v, expected %v", changed, test.changed)
+			}
+		})
+	}
+}
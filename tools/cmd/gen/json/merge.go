@@ -0,0 +1,111 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package json
+
+import "fmt"
+
+// Merge returns a new MetaModel holding the declarations of base followed by
+// the declarations of ext. ext is typically an extensions file describing
+// vendor-specific methods and types, in the same shape as the LSP meta model.
+// Merge returns an error if ext declares a method or type with a name already
+// declared by base, or declared more than once by ext.
+func Merge(base, ext MetaModel) (MetaModel, error) {
+	methods := map[string]struct{}{}
+	for _, r := range base.Requests {
+		methods[r.Method] = struct{}{}
+	}
+	for _, n := range base.Notifications {
+		methods[n.Method] = struct{}{}
+	}
+	addMethod := func(method string) error {
+		if _, found := methods[method]; found {
+			return fmt.Errorf("extension method '%v' collides with an existing method", method)
+		}
+		methods[method] = struct{}{}
+		return nil
+	}
+
+	types := map[string]struct{}{}
+	for _, s := range base.Structures {
+		types[s.Name] = struct{}{}
+	}
+	for _, e := range base.Enumerations {
+		types[e.Name] = struct{}{}
+	}
+	for _, a := range base.TypeAliases {
+		types[a.Name] = struct{}{}
+	}
+	addType := func(name string) error {
+		if _, found := types[name]; found {
+			return fmt.Errorf("extension type '%v' collides with an existing type", name)
+		}
+		types[name] = struct{}{}
+		return nil
+	}
+
+	for _, r := range ext.Requests {
+		if err := addMethod(r.Method); err != nil {
+			return MetaModel{}, err
+		}
+	}
+	for _, n := range ext.Notifications {
+		if err := addMethod(n.Method); err != nil {
+			return MetaModel{}, err
+		}
+	}
+	for _, s := range ext.Structures {
+		if err := addType(s.Name); err != nil {
+			return MetaModel{}, err
+		}
+	}
+	for _, e := range ext.Enumerations {
+		if err := addType(e.Name); err != nil {
+			return MetaModel{}, err
+		}
+	}
+	for _, a := range ext.TypeAliases {
+		if err := addType(a.Name); err != nil {
+			return MetaModel{}, err
+		}
+	}
+
+	return MetaModel{
+		Enumerations:  concat(base.Enumerations, ext.Enumerations),
+		MetaData:      base.MetaData,
+		Notifications: concat(base.Notifications, ext.Notifications),
+		Requests:      concat(base.Requests, ext.Requests),
+		Structures:    concat(base.Structures, ext.Structures),
+		TypeAliases:   concat(base.TypeAliases, ext.TypeAliases),
+	}, nil
+}
+
+// concat returns a new slice holding the elements of a followed by b
+func concat[T any](a, b []T) []T {
+	out := make([]T, 0, len(a)+len(b))
+	return append(append(out, a...), b...)
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package json_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/langsvr/tools/cmd/gen/json"
+	"github.com/google/langsvr/tools/cmd/gen/resolver"
+	"github.com/google/langsvr/tools/fileutils"
+	"github.com/google/langsvr/tools/template"
+)
+
+const base = `{
+	"metaData": { "version": "3.17.0" },
+	"requests": [{
+		"method": "textDocument/hover",
+		"messageDirection": "clientToServer",
+		"params": { "kind": "reference", "name": "HoverParams" },
+		"result": { "kind": "reference", "name": "Hover" }
+	}],
+	"notifications": [{
+		"method": "$/cancelRequest",
+		"messageDirection": "both"
+	}],
+	"structures": [
+		{ "name": "Hover", "properties": [] },
+		{ "name": "HoverParams", "properties": [] }
+	],
+	"enumerations": [],
+	"typeAliases": []
+}`
+
+func decode(t *testing.T, s string) json.MetaModel {
+	t.Helper()
+	model, err := json.Decode(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("json.Decode() failed with %v", err)
+	}
+	return model
+}
+
+func TestMerge(t *testing.T) {
+	ext := decode(t, `{
+		"requests": [{
+			"method": "$/acme/reindex",
+			"messageDirection": "clientToServer",
+			"params": { "kind": "reference", "name": "ReindexParams" },
+			"result": { "kind": "base", "name": "null" }
+		}],
+		"structures": [{ "name": "ReindexParams", "properties": [] }]
+	}`)
+
+	merged, err := json.Merge(decode(t, base), ext)
+	if err != nil {
+		t.Fatalf("json.Merge() failed with %v", err)
+	}
+
+	methods := []string{}
+	for _, r := range merged.Requests {
+		methods = append(methods, r.Method)
+	}
+	if got, expect := strings.Join(methods, ","), "textDocument/hover,$/acme/reindex"; got != expect {
+		t.Errorf("merged requests were %v, expected %v", got, expect)
+	}
+	if got, expect := len(merged.Structures), 3; got != expect {
+		t.Errorf("merged model has %v structures, expected %v", got, expect)
+	}
+	if got, expect := merged.MetaData.Version, "3.17.0"; got != expect {
+		t.Errorf("merged model version was %v, expected %v", got, expect)
+	}
+}
+
+func TestMergeGenerates(t *testing.T) {
+	ext := decode(t, `{
+		"requests": [{
+			"method": "$/acme/reindex",
+			"messageDirection": "clientToServer",
+			"params": { "kind": "reference", "name": "ReindexParams" },
+			"result": { "kind": "base", "name": "null" }
+		}],
+		"structures": [{ "name": "ReindexParams", "properties": [] }]
+	}`)
+
+	merged, err := json.Merge(decode(t, base), ext)
+	if err != nil {
+		t.Fatalf("json.Merge() failed with %v", err)
+	}
+	p, err := resolver.Resolve(merged)
+	if err != nil {
+		t.Fatalf("resolver.Resolve() failed with %v", err)
+	}
+	funcs := template.Functions{
+		"SinceGuard": func(string) (string, error) { return "", nil },
+	}
+
+	for _, test := range []struct {
+		template string
+		expect   []string
+	}{
+		{
+			template: "include/langsvr/lsp/lsp.h.tmpl",
+			expect: []string{
+				"struct AcmeReindexRequest : lsp::ReindexParams {",
+				`static constexpr std::string_view kMethod = "$/acme/reindex";`,
+			},
+		},
+		{
+			template: "src/lsp/lsp.cc.tmpl",
+			expect: []string{
+				"Decode([[maybe_unused]] V& v, [[maybe_unused]] ReindexParams& out)",
+				"Encode([[maybe_unused]] const ReindexParams& in, [[maybe_unused]] json::Builder& b)",
+			},
+		},
+	} {
+		tmpl, err := template.FromFile(filepath.Join(fileutils.ProjectRoot(), test.template))
+		if err != nil {
+			t.Fatalf("template.FromFile() failed with %v", err)
+		}
+		sb := strings.Builder{}
+		if err := tmpl.Run(&sb, p, funcs); err != nil {
+			t.Fatalf("template.Run() failed with %v", err)
+		}
+		for _, expect := range test.expect {
+			if !strings.Contains(sb.String(), expect) {
+				t.Errorf("'%v' output does not contain '%v'", test.template, expect)
+			}
+		}
+	}
+}
+
+func TestMergeCollisions(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		ext  string
+		err  string
+	}{
+		{
+			name: "request with standard method",
+			ext:  `{"requests": [{"method": "textDocument/hover", "messageDirection": "clientToServer", "result": {"kind": "base", "name": "null"}}]}`,
+			err:  "extension method 'textDocument/hover' collides with an existing method",
+		},
+		{
+			name: "notification with standard method",
+			ext:  `{"notifications": [{"method": "$/cancelRequest", "messageDirection": "both"}]}`,
+			err:  "extension method '$/cancelRequest' collides with an existing method",
+		},
+		{
+			name: "duplicate extension method",
+			ext:  `{"notifications": [{"method": "$/acme/ping", "messageDirection": "both"}, {"method": "$/acme/ping", "messageDirection": "both"}]}`,
+			err:  "extension method '$/acme/ping' collides with an existing method",
+		},
+		{
+			name: "structure with standard name",
+			ext:  `{"structures": [{"name": "Hover", "properties": []}]}`,
+			err:  "extension type 'Hover' collides with an existing type",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := json.Merge(decode(t, base), decode(t, test.ext))
+			if err == nil {
+				t.Fatalf("json.Merge() returned no error")
+			}
+			if got := err.Error(); got != test.err {
+				t.Errorf("json.Merge() returned error '%v', expected '%v'", got, test.err)
+			}
+		})
+	}
+}
@@ -54,3 +54,9 @@ type Structure struct {
 }
 
 func (*Structure) isTypeDecl() {}
+
+// Bases returns the structures that the structure is derived from in C++: the
+// extended structures followed by the mixins
+func (s *Structure) Bases() []Type {
+	return append(append([]Type{}, s.Extends...), s.Mixins...)
+}
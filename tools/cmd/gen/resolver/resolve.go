@@ -432,8 +432,8 @@ func (r *resolver) sortStructures(in []*protocol.Structure) []*protocol.Structur
 			return
 		}
 		seen[s.Name] = struct{}{}
-		for _, ext := range s.Extends {
-			if ref, ok := ext.(*protocol.ReferenceType); ok {
+		for _, base := range s.Bases() {
+			if ref, ok := base.(*protocol.ReferenceType); ok {
 				if dep, ok := structures[ref.Name]; ok {
 					visit(dep)
 				}
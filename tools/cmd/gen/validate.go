@@ -0,0 +1,293 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/langsvr/tools/cmd/gen/protocol"
+)
+
+// member is a C++ structure data member, parsed from the generated header
+type member struct {
+	name     string
+	optional bool
+}
+
+// validate checks the generated C++ header and source against the protocol.
+// validate returns a description of each structure property that is missing,
+// unexpected, or has an optionality that differs from the meta model, and of
+// each request and notification that is missing or is declared with a message
+// direction that differs from the meta model.
+func validate(p *protocol.Protocol, header, source string) []string {
+	members := parseMembers(header)
+	decoded, encoded := parseJsonNames(source)
+
+	problems := validateDirections(p, parseDirections(header))
+	var check func(s *protocol.Structure)
+	check = func(s *protocol.Structure) {
+		name := strings.Join(s.NestedNames, "::")
+
+		// Check the C++ data members declared in the header
+		if got, ok := members[name]; !ok {
+			problems = append(problems, fmt.Sprintf("structure '%v' is not declared in the header", name))
+		} else {
+			expect := make([]member, len(s.Properties))
+			for i, prop := range s.Properties {
+				expect[i] = member{prop.CppName, prop.Optional}
+			}
+			problems = append(problems, diff(name, "member", expect, got, func(m member) string {
+				if m.optional {
+					return "Optional<> " + m.name
+				}
+				return m.name
+			})...)
+		}
+
+		// Check the JSON names used by Decode() and Encode().
+		// Decode() matches the 'kind' with MatchKind(), Encode() emits it as a member.
+		jsonNames := make([]string, len(s.Properties))
+		for i, prop := range s.Properties {
+			jsonNames[i] = prop.JsonName
+		}
+		encodedNames := jsonNames
+		if s.Kind != "" {
+			encodedNames = append(append([]string{}, jsonNames...), "kind")
+		}
+		for _, fn := range []struct {
+			name   string
+			names  map[string][]string
+			expect []string
+		}{
+			{"Decode()", decoded, jsonNames},
+			{"Encode()", encoded, encodedNames},
+		} {
+			if got, ok := fn.names[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%v for structure '%v' is not defined in the source", fn.name, name))
+			} else {
+				problems = append(problems, diff(name, fn.name+" JSON name", unique(fn.expect), unique(got), func(s string) string { return s })...)
+			}
+		}
+
+		for _, nested := range s.NestedStructures {
+			check(nested)
+		}
+	}
+	for _, s := range p.Structures {
+		check(s)
+	}
+	return problems
+}
+
+// cppDirections maps the meta model message direction to the C++ enumerator
+// emitted for the message's kMessageDirection
+var cppDirections = map[protocol.MessageDirection]string{
+	protocol.MessageDirectionClientToServer: "kClientToServer",
+	protocol.MessageDirectionServerToClient: "kServerToClient",
+	protocol.MessageDirectionBidirectional:  "kBoth",
+}
+
+// validateDirections returns a description of each request and notification
+// in the protocol that is missing from directions, or that has a direction that
+// differs from the meta model. directions is keyed by method name.
+func validateDirections(p *protocol.Protocol, directions map[string]string) []string {
+	problems := []string{}
+	check := func(kind, method string, dir protocol.MessageDirection) {
+		expect := cppDirections[dir]
+		if got, ok := directions[method]; !ok {
+			problems = append(problems, fmt.Sprintf("%v '%v' is not declared in the header", kind, method))
+		} else if got != expect {
+			problems = append(problems, fmt.Sprintf("%v '%v' has message direction '%v', expected '%v'", kind, method, got, expect))
+		}
+	}
+	for _, r := range p.Requests {
+		check("request", r.Method, r.MessageDirection)
+	}
+	for _, n := range p.Notifications {
+		check("notification", n.Method, n.MessageDirection)
+	}
+	return problems
+}
+
+// diff returns a description of each item that is in expect but not in got,
+// and each item that is in got but not in expect.
+func diff[T comparable](structure, what string, expect, got []T, str func(T) string) []string {
+	problems := []string{}
+	gotSet := map[T]bool{}
+	for _, g := range got {
+		gotSet[g] = true
+	}
+	expectSet := map[T]bool{}
+	for _, e := range expect {
+		expectSet[e] = true
+		if !gotSet[e] {
+			problems = append(problems, fmt.Sprintf("structure '%v' is missing %v '%v'", structure, what, str(e)))
+		}
+	}
+	for _, g := range got {
+		if !expectSet[g] {
+			problems = append(problems, fmt.Sprintf("structure '%v' has unexpected %v '%v'", structure, what, str(g)))
+		}
+	}
+	return problems
+}
+
+// unique returns the sorted, deduplicated list of strings
+func unique(list []string) []string {
+	set := map[string]struct{}{}
+	for _, s := range list {
+		set[s] = struct{}{}
+	}
+	out := make([]string, 0, len(set))
+	for s := range set {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var (
+	commentRE = regexp.MustCompile(`//[^\n]*`)
+	tokenRE   = regexp.MustCompile(`[A-Za-z_]\w*|::|"(?:[^"\\]|\\.)*"|\S`)
+)
+
+// parseMembers parses the generated header, returning the data members of each
+// structure, keyed by the fully qualified structure name (e.g. 'Outer::Inner').
+func parseMembers(header string) map[string][]member {
+	tokens := tokenRE.FindAllString(commentRE.ReplaceAllString(header, ""), -1)
+	out := map[string][]member{}
+
+	// scope is the stack of open braces. Structures push their name, other
+	// scopes (e.g. namespaces) push an empty string.
+	scope := []string{}
+	structName := func() string {
+		names := []string{}
+		for _, s := range scope {
+			if s != "" {
+				names = append(names, s)
+			}
+		}
+		return strings.Join(names, "::")
+	}
+	inStruct := func() bool { return len(scope) > 0 && scope[len(scope)-1] != "" }
+
+	statement := []string{}
+	for i := 0; i < len(tokens); i++ {
+		switch tok := tokens[i]; {
+		case tok == "enum":
+			// Skip over the enum body
+			for i < len(tokens) && tokens[i] != "}" && tokens[i] != ";" {
+				i++
+			}
+			statement = statement[:0]
+		case tok == "struct" && len(statement) == 0 && i+1 < len(tokens):
+			// Structure definition or forward declaration
+			name := tokens[i+1]
+			for i < len(tokens) && tokens[i] != "{" && tokens[i] != ";" {
+				i++
+			}
+			if i < len(tokens) && tokens[i] == "{" {
+				scope = append(scope, name)
+				if _, ok := out[structName()]; !ok {
+					out[structName()] = []member{}
+				}
+			}
+		case tok == "{" && i+1 < len(tokens) && tokens[i+1] == "}":
+			// Value initializer
+			i++
+		case tok == "{":
+			scope = append(scope, "")
+			statement = statement[:0]
+		case tok == "}":
+			if len(scope) > 0 {
+				scope = scope[:len(scope)-1]
+			}
+			statement = statement[:0]
+		case tok == ";":
+			if inStruct() && len(statement) > 0 {
+				switch statement[0] {
+				case "static", "using":
+				default:
+					out[structName()] = append(out[structName()], member{
+						name:     statement[len(statement)-1],
+						optional: statement[0] == "Optional",
+					})
+				}
+			}
+			statement = statement[:0]
+		default:
+			statement = append(statement, tok)
+		}
+	}
+	return out
+}
+
+var directionRE = regexp.MustCompile(`kMethod\s*=\s*"([^"]*)";[^}]*?kMessageDirection\s*=\s*MessageDirection::(\w+);`)
+
+// parseDirections parses the generated header, returning the C++ enumerator
+// of each message's kMessageDirection, keyed by the message's method name.
+func parseDirections(header string) map[string]string {
+	out := map[string]string{}
+	for _, m := range directionRE.FindAllStringSubmatch(header, -1) {
+		out[m[1]] = m[2]
+	}
+	return out
+}
+
+var (
+	functionRE = regexp.MustCompile(`(Decode|Encode)\(\s*\[\[maybe_unused\]\]\s+(?:const\s+)?(?:V|([\w:]+))&\s+(?:v|in),\s*\[\[maybe_unused\]\]\s+(?:([\w:]+)&\s+out|json::Builder&\s+b)\)`)
+	jsonRE     = regexp.MustCompile(`v\.(?:Get|Has)\("([^"]*)"\)|Member\{\s*"([^"]*)"`)
+)
+
+// parseJsonNames parses the generated source, returning the JSON names used by
+// the structure Decode() and Encode() functions, keyed by the fully qualified
+// structure name.
+func parseJsonNames(source string) (decoded, encoded map[string][]string) {
+	decoded, encoded = map[string][]string{}, map[string][]string{}
+	matches := functionRE.FindAllStringSubmatchIndex(source, -1)
+	for i, match := range matches {
+		// The function body ends at the next structure function
+		end := len(source)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		names := []string{}
+		for _, m := range jsonRE.FindAllStringSubmatch(source[match[1]:end], -1) {
+			names = append(names, m[1]+m[2])
+		}
+		if source[match[2]:match[3]] == "Decode" {
+			decoded[source[match[6]:match[7]]] = names
+		} else {
+			encoded[source[match[4]:match[5]]] = names
+		}
+	}
+	return decoded, encoded
+}
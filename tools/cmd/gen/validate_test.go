@@ -0,0 +1,213 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/langsvr/tools/cmd/gen/json"
+	"github.com/google/langsvr/tools/cmd/gen/protocol"
+	"github.com/google/langsvr/tools/cmd/gen/resolver"
+	"github.com/google/langsvr/tools/fileutils"
+	"github.com/google/langsvr/tools/template"
+)
+
+const validateModel = `{
+	"metaData": { "version": "3.17.0" },
+	"requests": [
+		{
+			"method": "shutdown",
+			"messageDirection": "clientToServer",
+			"result": { "kind": "base", "name": "null" }
+		}
+	],
+	"notifications": [
+		{ "method": "telemetry/event", "messageDirection": "serverToClient" }
+	],
+	"structures": [
+		{
+			"name": "CreateFile",
+			"properties": [
+				{ "name": "kind", "type": { "kind": "stringLiteral", "value": "create" } },
+				{ "name": "uri", "type": { "kind": "base", "name": "DocumentUri" } },
+				{ "name": "ignoreIfExists", "optional": true, "type": { "kind": "base", "name": "boolean" } }
+			]
+		},
+		{
+			"name": "Options",
+			"properties": [
+				{
+					"name": "range",
+					"optional": true,
+					"type": {
+						"kind": "literal",
+						"value": { "properties": [
+							{ "name": "start", "type": { "kind": "base", "name": "uinteger" } }
+						] }
+					}
+				}
+			]
+		}
+	],
+	"enumerations": [],
+	"typeAliases": []
+}`
+
+// generate returns the resolved protocol, and the header and source generated
+// from the meta model JSON
+func generate(t *testing.T, model string) (p *protocol.Protocol, header, source string) {
+	t.Helper()
+	return generateWithMinVersion(t, model, "")
+}
+
+// generateWithMinVersion is generate, with the '-min-version' flag set to minVersion
+func generateWithMinVersion(t *testing.T, model, minVersion string) (p *protocol.Protocol, header, source string) {
+	t.Helper()
+	funcs, err := functions(minVersion)
+	if err != nil {
+		t.Fatalf("functions() failed with %v", err)
+	}
+	m, err := json.Decode(strings.NewReader(model))
+	if err != nil {
+		t.Fatalf("json.Decode() failed with %v", err)
+	}
+	p, err = resolver.Resolve(m)
+	if err != nil {
+		t.Fatalf("resolver.Resolve() failed with %v", err)
+	}
+	out := []string{}
+	for _, relPath := range []string{"include/langsvr/lsp/lsp.h.tmpl", "src/lsp/lsp.cc.tmpl"} {
+		tmpl, err := template.FromFile(filepath.Join(fileutils.ProjectRoot(), relPath))
+		if err != nil {
+			t.Fatalf("template.FromFile() failed with %v", err)
+		}
+		sb := strings.Builder{}
+		if err := tmpl.Run(&sb, p, funcs); err != nil {
+			t.Fatalf("template.Run() failed with %v", err)
+		}
+		out = append(out, sb.String())
+	}
+	return p, withSurfaceHash(out[0]), out[1]
+}
+
+func TestValidate(t *testing.T) {
+	p, header, source := generate(t, validateModel)
+
+	for _, test := range []struct {
+		name   string
+		header func(string) string
+		source func(string) string
+		expect []string
+	}{
+		{
+			name:   "generated",
+			expect: []string{},
+		},
+		{
+			name:   "renamed member",
+			header: func(s string) string { return strings.Replace(s, "uri{}", "url{}", 1) },
+			expect: []string{
+				"structure 'CreateFile' is missing member 'uri'",
+				"structure 'CreateFile' has unexpected member 'url'",
+			},
+		},
+		{
+			name: "required member declared optional",
+			header: func(s string) string {
+				return strings.Replace(s, "DocumentUri uri{};", "Optional<DocumentUri> uri;", 1)
+			},
+			expect: []string{
+				"structure 'CreateFile' is missing member 'uri'",
+				"structure 'CreateFile' has unexpected member 'Optional<> uri'",
+			},
+		},
+		{
+			name:   "missing nested structure",
+			header: func(s string) string { return strings.Replace(s, "struct Range {", "struct Span {", 1) },
+			expect: []string{"structure 'Options::Range' is not declared in the header"},
+		},
+		{
+			name: "mistyped JSON name",
+			source: func(s string) string {
+				return strings.Replace(s, `v.Get("ignoreIfExists")`, `v.Get("ignoreIfExist")`, 1)
+			},
+			expect: []string{
+				"structure 'CreateFile' has unexpected Decode() JSON name 'ignoreIfExist'",
+			},
+		},
+		{
+			name:   "missing encoded kind",
+			source: func(s string) string { return strings.Replace(s, `Member{"kind"`, `Member{"type"`, 1) },
+			expect: []string{
+				"structure 'CreateFile' is missing Encode() JSON name 'kind'",
+				"structure 'CreateFile' has unexpected Encode() JSON name 'type'",
+			},
+		},
+		{
+			name: "mismatched request direction",
+			header: func(s string) string {
+				return strings.Replace(s, "MessageDirection::kClientToServer;", "MessageDirection::kBoth;", 1)
+			},
+			expect: []string{"request 'shutdown' has message direction 'kBoth', expected 'kClientToServer'"},
+		},
+		{
+			name: "mismatched notification direction",
+			header: func(s string) string {
+				return strings.Replace(s, "MessageDirection::kServerToClient;", "MessageDirection::kClientToServer;", 1)
+			},
+			expect: []string{
+				"notification 'telemetry/event' has message direction 'kClientToServer', expected 'kServerToClient'",
+			},
+		},
+		{
+			name:   "missing notification",
+			header: func(s string) string { return strings.Replace(s, `"telemetry/event"`, `"telemetry/events"`, 1) },
+			expect: []string{"notification 'telemetry/event' is not declared in the header"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h, s := header, source
+			if test.header != nil {
+				if h = test.header(header); h == header {
+					t.Fatalf("header was not modified")
+				}
+			}
+			if test.source != nil {
+				if s = test.source(source); s == source {
+					t.Fatalf("source was not modified")
+				}
+			}
+			got := validate(p, h, s)
+			if strings.Join(got, "\n") != strings.Join(test.expect, "\n") {
+				t.Errorf("validate() returned:\n%v\nexpected:\n%v", strings.Join(got, "\n"), strings.Join(test.expect, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/langsvr/tools/template"
+)
+
+// version is a parsed LSP version number, such as '3.17.0'
+type version []int
+
+// parseVersion parses the dot-separated version string s
+func parseVersion(s string) (version, error) {
+	parts := strings.Split(s, ".")
+	out := make(version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version '%v'", s)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// after returns true if v is a later version than o. Missing trailing
+// components are treated as zero, so '3.17' is equal to '3.17.0'.
+func (v version) after(o version) bool {
+	for i := 0; i < len(v) || i < len(o); i++ {
+		a, b := 0, 0
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+// functions returns the template functions used by the lsp.h and lsp.cc
+// templates.
+//
+// SinceGuard returns the name of the preprocessor macro that guards a
+// declaration that has been available since the given version, or an empty
+// string if the declaration needs no guard. Declarations are only guarded if
+// they were introduced after minVersion. If minVersion is empty, nothing is
+// guarded. The meta model may follow the version with a note, as in
+// '3.16 - support for default behavior'. Only the leading version is used.
+func functions(minVersion string) (template.Functions, error) {
+	var baseline version
+	if minVersion != "" {
+		v, err := parseVersion(minVersion)
+		if err != nil {
+			return nil, fmt.Errorf("-min-version: %w", err)
+		}
+		baseline = v
+	}
+	return template.Functions{
+		"SinceGuard": func(since string) (string, error) {
+			if baseline == nil || strings.TrimSpace(since) == "" {
+				return "", nil
+			}
+			v, err := parseVersion(strings.Fields(since)[0])
+			if err != nil {
+				return "", err
+			}
+			if !v.after(baseline) {
+				return "", nil
+			}
+			if len(v) < 2 {
+				v = append(v, 0)
+			}
+			return fmt.Sprintf("LANGSVR_LSP_%v_%v", v[0], v[1]), nil
+		},
+	}, nil
+}
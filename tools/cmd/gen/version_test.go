@@ -0,0 +1,104 @@
+// Copyright 2024 The langsvr Authors
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/langsvr/tools/fileutils"
+)
+
+func TestSinceGuard(t *testing.T) {
+	for _, test := range []struct {
+		minVersion, since, expect string
+	}{
+		{"", "3.18.0", ""},
+		{"3.17", "", ""},
+		{"3.17", "3.16.0", ""},
+		{"3.17", "3.17.0", ""},
+		{"3.17.0", "3.17", ""},
+		{"3.17", "3.17.1", "LANGSVR_LSP_3_17"},
+		{"3.17", "3.18.0", "LANGSVR_LSP_3_18"},
+		{"3.16.5", "4", "LANGSVR_LSP_4_0"},
+		{"3.17", "3.16 - support for default behavior", ""},
+		{"3.16", "3.17.0 - support for WorkspaceSymbol", "LANGSVR_LSP_3_17"},
+	} {
+		funcs, err := functions(test.minVersion)
+		if err != nil {
+			t.Fatalf("functions(%q) failed with %v", test.minVersion, err)
+		}
+		got, err := funcs["SinceGuard"].(func(string) (string, error))(test.since)
+		if err != nil {
+			t.Errorf("SinceGuard(%q) with min version %q failed with %v", test.since, test.minVersion, err)
+			continue
+		}
+		if got != test.expect {
+			t.Errorf("SinceGuard(%q) with min version %q returned %q, expected %q",
+				test.since, test.minVersion, got, test.expect)
+		}
+	}
+}
+
+func TestSinceGuardInvalidVersion(t *testing.T) {
+	if _, err := functions("3.x"); err == nil {
+		t.Errorf("functions(\"3.x\") should have failed")
+	}
+	funcs, err := functions("3.17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := funcs["SinceGuard"].(func(string) (string, error))("next"); err == nil {
+		t.Errorf("SinceGuard(\"next\") should have failed")
+	}
+}
+
+func TestMinVersionGuardsNewMethods(t *testing.T) {
+	model, err := os.ReadFile(filepath.Join(fileutils.ThisDir(), goldenModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, header, source := generateWithMinVersion(t, string(model), "3.17")
+
+	// textDocument/inlineCompletion is since 3.18.0, and should be guarded
+	begin := strings.Index(header, "#ifdef LANGSVR_LSP_3_18")
+	decl := strings.Index(header, "struct TextDocumentInlineCompletionRequest")
+	end := strings.Index(header, "#endif  // LANGSVR_LSP_3_18")
+	if begin < 0 || decl < begin || end < decl {
+		t.Errorf("TextDocumentInlineCompletionRequest is not guarded by LANGSVR_LSP_3_18:\n%v", header)
+	}
+	if strings.Count(header, "#ifdef") != 1 {
+		t.Errorf("expected exactly one guard, got %v", strings.Count(header, "#ifdef"))
+	}
+
+	if problems := validate(p, header, source); len(problems) > 0 {
+		t.Errorf("validate() returned problems: %v", problems)
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"text/template"
 
 	"github.com/google/langsvr/tools/fileutils"
+	"github.com/google/langsvr/tools/text"
 )
 
 // Functions is an alias to the template function binding table
@@ -77,14 +78,23 @@ func (t *Template) Run(w io.Writer, data any, funcs Functions) error {
 
 	// Add a bunch of generic useful functions
 	g.funcs = Functions{
-		"Eval":   g.eval,
-		"Import": g.importTmpl,
-		"Is":     is,
-		"Split":  strings.Split,
-		"Join":   strings.Join,
-		"Title":  strings.Title,
-		"Sum":    sum,
-		"Error":  func(err string, args ...any) string { panic(fmt.Errorf(err, args...)) },
+		"Contains":   strings.Contains,
+		"Eval":       g.eval,
+		"HasPrefix":  strings.HasPrefix,
+		"Import":     g.importTmpl,
+		"Is":         is,
+		"Iterate":    iterate,
+		"Map":        newMap,
+		"PascalCase": text.PascalCase,
+		"Split":      strings.Split,
+		"Join":       strings.Join,
+		"Title":      strings.Title,
+		"TrimLeft":   strings.TrimLeft,
+		"TrimPrefix": strings.TrimPrefix,
+		"TrimRight":  strings.TrimRight,
+		"TrimSuffix": strings.TrimSuffix,
+		"Sum":        sum,
+		"Error":      func(err string, args ...any) string { panic(fmt.Errorf(err, args...)) },
 	}
 
 	// Append custom functions
@@ -187,3 +197,31 @@ func sum(numbers ...int) int {
 	}
 	return n
 }
+
+// iterate returns a slice of length n, with each element set to its index.
+// Useful for emitting a loop of n iterations in a template
+func iterate(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// Map is a simple generic key-value map, which can be used in a template
+type Map map[any]any
+
+func newMap() Map { return Map{} }
+
+// Put adds the key-value pair into the map.
+// Put always returns an empty string so nothing is printed in the template.
+func (m Map) Put(key, value any) string {
+	m[key] = value
+	return ""
+}
+
+// Get looks up and returns the value with the given key. If the map does not
+// contain the given key, then nil is returned.
+func (m Map) Get(key any) any {
+	return m[key]
+}